@@ -0,0 +1,171 @@
+package youtube
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	fhttp "github.com/bogdanfinn/fhttp"
+)
+
+//////////////////////////////////////////////////
+
+// Reason for which YouTube refused to serve a (scraping) request.
+type BlockReason uint
+
+const (
+	BlockNone BlockReason = iota
+	// HTTP 429 Too Many Requests.
+	BlockRateLimited
+	// Redirect to Google's "unusual traffic" captcha page (google.com/sorry).
+	BlockCaptcha
+	// Redirect to the consent wall (consent.youtube.com), i.e., the consent
+	// cookie has not been accepted.
+	BlockConsent
+)
+
+var blockReasonNames = map[BlockReason]string{
+	BlockNone:        "none",
+	BlockRateLimited: "rate_limited",
+	BlockCaptcha:     "captcha",
+	BlockConsent:     "consent",
+}
+
+func (r BlockReason) String() string {
+	if name, ok := blockReasonNames[r]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+func (r BlockReason) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// Classifies a response as blocked (judging by its status code, and by the
+// URL it was redirected to, if any); does not read the body.
+func DetectBlock(resp *fhttp.Response) BlockReason {
+	if resp == nil {
+		return BlockNone
+	}
+
+	if resp.StatusCode == fhttp.StatusTooManyRequests {
+		return BlockRateLimited
+	}
+
+	if resp.Request != nil {
+		if reason := blockReasonFromURL(resp.Request.URL); reason != BlockNone {
+			return reason
+		}
+	}
+
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		if location := resp.Header.Get("Location"); location != "" {
+			if u, err := url.Parse(location); err == nil {
+				return blockReasonFromURL(u)
+			}
+		}
+	}
+
+	return BlockNone
+}
+
+func blockReasonFromURL(u *url.URL) BlockReason {
+	if u == nil {
+		return BlockNone
+	}
+
+	host := strings.ToLower(u.Hostname())
+
+	switch {
+	case host == "consent.youtube.com" || host == "consent.google.com":
+		return BlockConsent
+
+	case (host == "google.com" || strings.HasSuffix(host, ".google.com")) && strings.HasPrefix(u.Path, "/sorry"):
+		return BlockCaptcha
+	}
+
+	return BlockNone
+}
+
+//////////////////////////////////////////////////
+
+var (
+	Blocked     = errors.New("blocked by YouTube")
+	CircuitOpen = errors.New("circuit breaker is open")
+)
+
+// Returned when YouTube refuses to serve a request (rate limit, captcha or
+// consent wall); matches Blocked (via errors.Is).
+type BlockedError struct {
+	Endpoint   Endpoint
+	Reason     BlockReason
+	URL        string
+	StatusCode int
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("%s: blocked (%s, HTTP %d)", e.Endpoint, e.Reason, e.StatusCode)
+}
+
+func (e *BlockedError) Unwrap() error {
+	return Blocked
+}
+
+// Returned instead of making a request while the endpoint's circuit breaker is
+// open; matches CircuitOpen (via errors.Is).
+type CircuitOpenError struct {
+	Endpoint Endpoint
+	Until    time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s: circuit breaker is open until %s", e.Endpoint, e.Until.Format(time.RFC3339))
+}
+
+func (e *CircuitOpenError) Unwrap() error {
+	return CircuitOpen
+}
+
+// Classifies an HTML page served in place of the expected content (e.g., a
+// feed) by looking for captcha and consent wall markers.
+func detectBlockBody(body []byte) BlockReason {
+	if !looksLikeHTML(body) {
+		return BlockNone
+	}
+
+	for _, marker := range captchaBodyMarkers {
+		if bytes.Contains(body, marker) {
+			return BlockCaptcha
+		}
+	}
+	for _, marker := range consentBodyMarkers {
+		if bytes.Contains(body, marker) {
+			return BlockConsent
+		}
+	}
+
+	return BlockNone
+}
+
+var (
+	captchaBodyMarkers = [][]byte{
+		[]byte("g-recaptcha"),
+		[]byte("www.google.com/sorry/"),
+		[]byte("unusual traffic from your computer network"),
+	}
+	consentBodyMarkers = [][]byte{
+		[]byte("consent.youtube.com/save"),
+		[]byte(`action="https://consent.youtube.com`),
+	}
+)
+
+func looksLikeHTML(body []byte) bool {
+	head := bytes.ToLower(bytes.TrimSpace(body[:min(len(body), 512)]))
+
+	return bytes.HasPrefix(head, []byte("<!doctype html")) || bytes.HasPrefix(head, []byte("<html"))
+}
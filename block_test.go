@@ -0,0 +1,50 @@
+package youtube_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	youtube "github.com/rubpy/crawly-live-youtube"
+)
+
+func TestFetchBlockedRedirect(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		want     youtube.BlockReason
+	}{
+		{"consent", "https://consent.youtube.com/m?continue=https%3A%2F%2Fwww.youtube.com%2F", youtube.BlockConsent},
+		{"captcha", "https://www.google.com/sorry/index?continue=https%3A%2F%2Fwww.youtube.com%2F", youtube.BlockCaptcha},
+		{"none", "/feeds/videos.xml?channel_id=" + testChannelID + "&redirected=1", youtube.BlockNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _, cr := newTestCrawler(t, youtube.DefaultSettings)
+
+			srv.Handle("/feeds/videos.xml", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Has("redirected") {
+					srv.Handle("/feeds/videos.xml", nil)
+				}
+
+				http.Redirect(w, r, tt.location, http.StatusFound)
+			}))
+
+			_, err := cr.FetchChannelXMLFeed(context.Background(), testChannelID)
+
+			if tt.want == youtube.BlockNone {
+				if err != nil {
+					t.Fatalf("got %v, want no error", err)
+				}
+				return
+			}
+
+			var be *youtube.BlockedError
+			if !errors.As(err, &be) || be.Reason != tt.want {
+				t.Fatalf("got %v, want a %s block", err, tt.want)
+			}
+		})
+	}
+}
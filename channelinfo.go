@@ -0,0 +1,122 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rubpy/crawly-live-youtube/xmlapi"
+	"github.com/rubpy/crawly/clog"
+)
+
+//////////////////////////////////////////////////
+
+// Metadata of a channel (title, handle, avatar, etc.), as last found on its
+// channel page.
+type ChannelInfo struct {
+	xmlapi.ChannelIndex
+
+	// When the page was last fetched in English (i.e., with counts that could
+	// be parsed; see countLanguage).
+	LastFetch time.Time `json:"last_fetch"`
+}
+
+// Returns the cached metadata of a channel; it is cached whenever a channel
+// page is fetched (i.e., when resolving a channel URL, via FetchChannelInfo,
+// and periodically for tracked channels; see MaximumCachedChannelInfoAge).
+func (cr *Crawler) ChannelInfo(channelID string) (info ChannelInfo, ok bool) {
+	return cr.channelInfoCache.Load(channelID)
+}
+
+// Caches the metadata found on a channel page; unless counts could be parsed
+// (i.e., the page was in English), those are kept from the previously cached
+// metadata, along with LastFetch (so that refreshChannelInfo still fetches
+// them).
+func (cr *Crawler) storeChannelInfo(index *xmlapi.ChannelIndex, counts bool) {
+	if index == nil || index.ChannelID == "" {
+		return
+	}
+
+	info := ChannelInfo{
+		ChannelIndex: *index,
+		LastFetch:    cr.now(),
+	}
+	if !counts {
+		prev, _ := cr.channelInfoCache.Load(index.ChannelID)
+
+		info.SubscriberCountText = prev.SubscriberCountText
+		info.Subscribers = prev.Subscribers
+		info.LastFetch = prev.LastFetch
+	}
+
+	cr.channelInfoCache.Store(index.ChannelID, info)
+}
+
+// Fetches the channel page, and caches (and returns) the channel's metadata.
+func (cr *Crawler) FetchChannelInfo(ctx context.Context, channelID string) (info ChannelInfo, err error) {
+	if channelID == "" || !IsValidChannelID(channelID) {
+		err = InvalidChannelID
+		return
+	}
+
+	// NOTE: fetched in English regardless of the request profile's language,
+	// as counts (e.g., of subscribers) can only be parsed in English (see
+	// xmlapi.ParseApproximateCount).
+	index, err := cr.fetchChannelIndex(ctx, "https://www.youtube.com/channel/"+channelID, countLanguage)
+	if err != nil {
+		return
+	}
+	if index.ChannelID != channelID {
+		err = fmt.Errorf("channel page refers to another channel (%q)", index.ChannelID)
+		return
+	}
+
+	info, _ = cr.ChannelInfo(channelID)
+	return info, nil
+}
+
+// Refreshes the cached metadata of a tracked channel, if older than
+// MaximumCachedChannelInfoAge; failures are only logged, and backed off (see
+// FailureState.fail, up to MaximumCachedChannelInfoAge), but never parked.
+func (cr *Crawler) refreshChannelInfo(ctx context.Context, channelID string, settings CrawlerSettings) {
+	if settings.MaximumCachedChannelInfoAge <= 0 {
+		return
+	}
+
+	if info, ok := cr.ChannelInfo(channelID); ok && cr.now().Sub(info.LastFetch) < settings.MaximumCachedChannelInfoAge {
+		return
+	}
+	if f, ok := cr.channelInfoFailures.Load(channelID); ok && !f.Ready(cr.now()) {
+		return
+	}
+
+	_, err := cr.FetchChannelInfo(ctx, channelID)
+
+	level := slog.LevelDebug
+	if err != nil {
+		if countsAsFailure(err) {
+			level = slog.LevelWarn
+		}
+
+		settings.MaximumFailures = 0
+
+		f, _ := cr.channelInfoFailures.Load(channelID)
+		f.fail(err, cr.now(), settings)
+		f.RetryAfter = f.LastFailure.Add(min(f.RetryAfter.Sub(f.LastFailure), settings.MaximumCachedChannelInfoAge))
+
+		cr.channelInfoFailures.Store(channelID, f)
+	} else {
+		cr.channelInfoFailures.Delete(channelID)
+	}
+
+	cr.Log(ctx, clog.Params{
+		Message: "fetchChannelInfo",
+		Level:   level,
+		Err:     err,
+
+		Values: clog.ParamGroup{
+			"channelID": channelID,
+		},
+	})
+}
@@ -0,0 +1,54 @@
+package youtube_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	youtube "github.com/rubpy/crawly-live-youtube"
+	"github.com/rubpy/crawly-live-youtube/youtubetest"
+)
+
+func TestChannelInfoCounts(t *testing.T) {
+	ctx := context.Background()
+
+	srv, clk, cr := newTestCrawler(t, youtube.DefaultSettings)
+
+	const channelID = "UCrPseYLGpNygVi34QpGNqpA"
+	if err := srv.AddChannel(youtubetest.Channel{ID: channelID, Title: "Counted", Subscribers: 1230000}); err != nil {
+		t.Fatalf("AddChannel: %v", err)
+	}
+
+	profile := youtube.DefaultRequestProfile
+	profile.Language = "de-DE"
+	if err := cr.SetRequestProfile(profile); err != nil {
+		t.Fatalf("SetRequestProfile: %v", err)
+	}
+
+	// NOTE: counts on a page in the profile's language are not cached.
+	if _, err := cr.FetchChannelIndex(ctx, "https://www.youtube.com/channel/"+channelID); err != nil {
+		t.Fatalf("FetchChannelIndex: %v", err)
+	}
+	info, ok := cr.ChannelInfo(channelID)
+	if !ok || info.Title != "Counted" {
+		t.Fatalf("got ChannelInfo %+v, %t", info, ok)
+	}
+	if info.Subscribers != 0 || !info.LastFetch.IsZero() {
+		t.Fatalf("got Subscribers %d, LastFetch %s from a non-English page", info.Subscribers, info.LastFetch)
+	}
+
+	if info, err := cr.FetchChannelInfo(ctx, channelID); err != nil {
+		t.Fatalf("FetchChannelInfo: %v", err)
+	} else if info.Subscribers != 1230000 || !info.LastFetch.Equal(clk.Now()) {
+		t.Fatalf("got Subscribers %d, LastFetch %s", info.Subscribers, info.LastFetch)
+	}
+	fetched := clk.Now()
+
+	clk.Advance(time.Hour)
+	if _, err := cr.FetchChannelIndex(ctx, "https://www.youtube.com/channel/"+channelID); err != nil {
+		t.Fatalf("FetchChannelIndex: %v", err)
+	}
+	if info, _ := cr.ChannelInfo(channelID); info.Subscribers != 1230000 || !info.LastFetch.Equal(fetched) {
+		t.Fatalf("got Subscribers %d, LastFetch %s after a non-English page", info.Subscribers, info.LastFetch)
+	}
+}
@@ -0,0 +1,45 @@
+package youtube
+
+import (
+	"context"
+
+	"github.com/rubpy/crawly"
+)
+
+//////////////////////////////////////////////////
+
+// Runs a single, standalone live detection pass for the given handle
+// (regardless of whether it is being tracked), and returns the resulting
+// entity data.
+func (cr *Crawler) Check(ctx context.Context, handle Handle) (data EntityData, err error) {
+	if !handle.Valid() {
+		err = crawly.InvalidHandle
+		return
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	} else {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+	}
+
+	order := crawly.Order{
+		Command: crawly.TrackingCommandStart,
+		Handle:  handle,
+	}
+
+	var tr crawly.TrackingResult
+	tr.Entity.Value.Handle = handle
+
+	if err = cr.orderHandler(ctx, &order, &tr); err != nil {
+		return
+	}
+
+	entity := tr.Entity.Value
+	err = cr.entityHandler(ctx, &entity, &tr)
+	data, _ = entity.Data.(EntityData)
+
+	return
+}
@@ -0,0 +1,207 @@
+package youtube
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	fhttp "github.com/bogdanfinn/fhttp"
+
+	"github.com/rubpy/crawly/clog"
+)
+
+//////////////////////////////////////////////////
+
+// State of an endpoint's circuit breaker.
+type CircuitStatus struct {
+	Endpoint Endpoint `json:"endpoint"`
+
+	Open      bool      `json:"open"`
+	OpenUntil time.Time `json:"open_until"`
+
+	ConsecutiveBlocks int         `json:"consecutive_blocks"`
+	LastBlock         BlockReason `json:"last_block"`
+	LastBlockTime     time.Time   `json:"last_block_time"`
+}
+
+type circuitBreakers struct {
+	mu sync.Mutex
+	m  map[Endpoint]*CircuitStatus
+}
+
+// NOTE: cb.mu must be held.
+func (cb *circuitBreakers) status(endpoint Endpoint) *CircuitStatus {
+	if cb.m == nil {
+		cb.m = map[Endpoint]*CircuitStatus{}
+	}
+
+	s, ok := cb.m[endpoint]
+	if !ok {
+		s = &CircuitStatus{Endpoint: endpoint}
+		cb.m[endpoint] = s
+	}
+
+	return s
+}
+
+//////////////////////////////////////////////////
+
+func (cr *Crawler) CircuitStatus(endpoint Endpoint) CircuitStatus {
+	now := cr.now()
+
+	cr.circuits.mu.Lock()
+	defer cr.circuits.mu.Unlock()
+
+	s := *cr.circuits.status(endpoint)
+	s.Open = now.Before(s.OpenUntil)
+
+	return s
+}
+
+// Returns the status of every endpoint's circuit breaker.
+func (cr *Crawler) CircuitStatuses() (statuses []CircuitStatus) {
+	for endpoint := range endpointNames {
+		statuses = append(statuses, cr.CircuitStatus(endpoint))
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Endpoint < statuses[j].Endpoint
+	})
+
+	return
+}
+
+// Closes the endpoint's circuit breaker (and forgets its blocks).
+func (cr *Crawler) ResetCircuit(endpoint Endpoint) {
+	cr.circuits.mu.Lock()
+	defer cr.circuits.mu.Unlock()
+
+	*cr.circuits.status(endpoint) = CircuitStatus{Endpoint: endpoint}
+}
+
+// Returns a CircuitOpenError if requests to the endpoint are currently
+// suspended.
+func (cr *Crawler) allowRequest(endpoint Endpoint) error {
+	now := cr.now()
+
+	cr.circuits.mu.Lock()
+	defer cr.circuits.mu.Unlock()
+
+	s := cr.circuits.status(endpoint)
+	if now.Before(s.OpenUntil) {
+		return &CircuitOpenError{
+			Endpoint: endpoint,
+			Until:    s.OpenUntil,
+		}
+	}
+
+	return nil
+}
+
+func (cr *Crawler) recordBlock(ctx context.Context, endpoint Endpoint, reason BlockReason) {
+	settings := cr.loadEffectiveSettings()
+	now := cr.now()
+
+	cr.circuits.mu.Lock()
+	s := cr.circuits.status(endpoint)
+	s.ConsecutiveBlocks++
+	s.LastBlock = reason
+	s.LastBlockTime = now
+
+	// NOTE: once the threshold has been reached, every block (e.g., the first
+	// request made after the cooldown) reopens the circuit.
+	open := settings.CircuitBreakerThreshold > 0 && s.ConsecutiveBlocks >= settings.CircuitBreakerThreshold
+	if open {
+		s.OpenUntil = now.Add(settings.CircuitBreakerCooldown)
+	}
+	status := *s
+	cr.circuits.mu.Unlock()
+
+	lp := clog.Params{
+		Message: "blocked",
+		Level:   slog.LevelWarn,
+
+		Values: clog.ParamGroup{
+			"endpoint":          endpoint.String(),
+			"reason":            reason.String(),
+			"consecutiveBlocks": status.ConsecutiveBlocks,
+		},
+	}
+	if open {
+		lp.Message = "circuitOpen"
+		lp.Set("until", status.OpenUntil)
+	}
+
+	cr.Log(ctx, lp)
+}
+
+func (cr *Crawler) recordSuccess(ctx context.Context, endpoint Endpoint) {
+	cr.circuits.mu.Lock()
+	s := cr.circuits.status(endpoint)
+	blocks := s.ConsecutiveBlocks
+	s.ConsecutiveBlocks = 0
+	s.OpenUntil = time.Time{}
+	cr.circuits.mu.Unlock()
+
+	if blocks > 0 {
+		cr.Log(ctx, clog.Params{
+			Message: "circuitClosed",
+			Level:   slog.LevelInfo,
+
+			Values: clog.ParamGroup{
+				"endpoint": endpoint.String(),
+			},
+		})
+	}
+}
+
+//////////////////////////////////////////////////
+
+// Makes a (scraping) request to the given endpoint, unless its circuit breaker
+// is open; responses recognized as blocked (by status code or redirect) are
+// turned into a BlockedError. Errors are returned as *FetchError.
+func (cr *Crawler) request(ctx context.Context, endpoint Endpoint, rawURL string, header http.Header) (resp *fhttp.Response, err error) {
+	if err = cr.allowRequest(endpoint); err != nil {
+		return nil, newFetchError(endpoint, rawURL, 0, nil, err)
+	}
+
+	resp, err = cr.client.Request(ctx, "GET", rawURL, nil, header)
+	if err != nil {
+		return nil, newFetchError(endpoint, rawURL, 0, nil, err)
+	}
+
+	if reason := DetectBlock(resp); reason != BlockNone {
+		resp.Body.Close()
+
+		cr.recordBlock(ctx, endpoint, reason)
+		return nil, newFetchError(endpoint, rawURL, resp.StatusCode, nil, &BlockedError{
+			Endpoint:   endpoint,
+			Reason:     reason,
+			URL:        rawURL,
+			StatusCode: resp.StatusCode,
+		})
+	}
+
+	return resp, nil
+}
+
+// Turns an unexpected response (i.e., one with a non-200 status code, or a
+// body which failed to parse) into a *FetchError, wrapping a BlockedError if
+// the body contains captcha or consent wall markers, and cause otherwise.
+func (cr *Crawler) responseError(ctx context.Context, endpoint Endpoint, rawURL string, resp *fhttp.Response, body []byte, cause error) error {
+	if reason := detectBlockBody(body); reason != BlockNone {
+		cr.recordBlock(ctx, endpoint, reason)
+
+		cause = &BlockedError{
+			Endpoint:   endpoint,
+			Reason:     reason,
+			URL:        rawURL,
+			StatusCode: resp.StatusCode,
+		}
+	}
+
+	return newFetchError(endpoint, rawURL, resp.StatusCode, body, cause)
+}
@@ -0,0 +1,36 @@
+package youtube
+
+import "time"
+
+//////////////////////////////////////////////////
+
+// Source of the current time for all time-dependent decisions of the
+// crawler (cache expiry, check delays, video age, etc.).
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Clock backed by time.Now (used unless WithClock is given).
+var SystemClock Clock = systemClock{}
+
+//////////////////////////////////////////////////
+
+func (cr *Crawler) now() time.Time {
+	if cr.clock == nil {
+		return time.Now()
+	}
+
+	return cr.clock.Now()
+}
+
+func (cr *Crawler) Clock() Clock {
+	if cr.clock == nil {
+		return SystemClock
+	}
+
+	return cr.clock
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rubpy/crawly"
+	cyoutube "github.com/rubpy/crawly-live-youtube"
+	"github.com/rubpy/crawly-live-youtube/configfile"
+	"github.com/rubpy/crawly-live-youtube/notify"
+	"github.com/rubpy/crawly-live-youtube/xmlapi"
+)
+
+//////////////////////////////////////////////////
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+// Resolves a handle to its channel ID (fetching the channel page, if needed).
+func resolveChannelID(ctx context.Context, cr *cyoutube.Crawler, handle cyoutube.Handle) (channelID string, err error) {
+	switch handle.Type {
+	case cyoutube.HandleChannelID:
+		return handle.Value, nil
+	case cyoutube.HandlePlaylistID:
+		return "", fmt.Errorf("%s is a playlist, not a channel", handle.Value)
+	}
+
+	index, err := cr.FetchChannelIndex(ctx, handle.Value)
+	if err != nil {
+		return "", fmt.Errorf("FetchChannelIndex: %w", err)
+	}
+
+	return index.ChannelID, nil
+}
+
+//////////////////////////////////////////////////
+
+func runResolve(ctx context.Context, args []string) (int, error) {
+	fs := flag.NewFlagSet("resolve", flag.ExitOnError)
+	var cf commonFlags
+	cf.register(fs)
+	fs.Parse(args)
+
+	handles, err := parseHandleArg(fs, 1)
+	if err != nil {
+		return exitError, err
+	}
+
+	cr, err := cf.newCrawler(ctx, false)
+	if err != nil {
+		return exitError, err
+	}
+
+	channelID, err := resolveChannelID(ctx, cr, handles[0])
+	if err != nil {
+		return exitError, err
+	}
+
+	fmt.Println(channelID)
+	return exitOK, nil
+}
+
+func runCheck(ctx context.Context, args []string) (int, error) {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	var cf commonFlags
+	cf.register(fs)
+	fs.Parse(args)
+
+	handles, err := parseHandleArg(fs, 1)
+	if err != nil {
+		return exitError, err
+	}
+
+	cr, err := cf.newCrawler(ctx, true)
+	if err != nil {
+		return exitError, err
+	}
+
+	handle := handles[0]
+	data, err := cr.Check(ctx, handle)
+	if err != nil {
+		return exitError, err
+	}
+
+	out := struct {
+		Handle     cyoutube.Handle `json:"handle"`
+		ChannelID  string          `json:"channel_id,omitempty"`
+		PlaylistID string          `json:"playlist_id,omitempty"`
+		Live       bool            `json:"live"`
+		LiveVideos []string        `json:"live_videos"`
+
+		Streams []cyoutube.LiveVideo `json:"streams,omitempty"`
+	}{
+		Handle:     handle,
+		Live:       data.Live,
+		LiveVideos: data.LiveVideos,
+
+		Streams: data.Streams,
+	}
+	switch canonical := cr.CanonicalHandle(handle); canonical.Type {
+	case cyoutube.HandleChannelID:
+		out.ChannelID = canonical.Value
+	case cyoutube.HandlePlaylistID:
+		out.PlaylistID = canonical.Value
+	}
+	if err := printJSON(out); err != nil {
+		return exitError, err
+	}
+
+	if !data.Live {
+		return exitNotLive, nil
+	}
+
+	return exitOK, nil
+}
+
+func runFeed(ctx context.Context, args []string) (int, error) {
+	fs := flag.NewFlagSet("feed", flag.ExitOnError)
+	var cf commonFlags
+	cf.register(fs)
+	fs.Parse(args)
+
+	handles, err := parseHandleArg(fs, 1)
+	if err != nil {
+		return exitError, err
+	}
+
+	cr, err := cf.newCrawler(ctx, false)
+	if err != nil {
+		return exitError, err
+	}
+
+	var feed *xmlapi.ChannelFeed
+	if handle := handles[0]; handle.Type == cyoutube.HandlePlaylistID {
+		if feed, err = cr.FetchPlaylistXMLFeed(ctx, handle.Value); err != nil {
+			return exitError, fmt.Errorf("FetchPlaylistXMLFeed: %w", err)
+		}
+	} else {
+		channelID, err := resolveChannelID(ctx, cr, handle)
+		if err != nil {
+			return exitError, err
+		}
+
+		if feed, err = cr.FetchChannelXMLFeed(ctx, channelID); err != nil {
+			return exitError, fmt.Errorf("FetchChannelXMLFeed: %w", err)
+		}
+	}
+
+	if err := printJSON(feed.Videos()); err != nil {
+		return exitError, err
+	}
+
+	return exitOK, nil
+}
+
+func runWatch(ctx context.Context, args []string) (int, error) {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	var cf commonFlags
+	cf.register(fs)
+	interval := fs.Duration("interval", 30*time.Second, "interval between crawler passes")
+	configPath := fs.String("config", "", "config file (settings and handles; reloaded on SIGHUP or change; its session settings apply unless -interval is given)")
+	dryRun := fs.Bool("notify-dry-run", false, "only log the notifications that the config file's rules would send")
+	fs.Parse(args)
+
+	var file *configfile.File
+	if *configPath != "" {
+		var err error
+		if file, err = configfile.Load(*configPath); err != nil {
+			return exitError, err
+		}
+
+		if cf.apiKey == "" {
+			cf.apiKey = file.APIKey
+		}
+	}
+
+	var handles []cyoutube.Handle
+	if file == nil || fs.NArg() > 0 {
+		var err error
+		if handles, err = parseHandleArg(fs, 0); err != nil {
+			return exitError, err
+		}
+	}
+
+	cr, err := cf.newCrawler(ctx, true)
+	if err != nil {
+		return exitError, err
+	}
+
+	sessionSettings := crawly.SessionSettings{
+		Interval:          *interval,
+		SinglePassTimeout: *interval + 15*time.Second,
+	}
+
+	var engine *notify.Engine
+	if file != nil {
+		if engine, err = notify.NewEngine(cr, notify.WithLogger(cr.Logger()), notify.WithDryRun(*dryRun)); err != nil {
+			return exitError, err
+		}
+		// NOTE: sinks are notified in the background (see Engine.Dispatch).
+		defer engine.Wait()
+
+		r, err := configfile.NewReloader(ctx, cr, *configPath, configfile.WithLogger(cr.Logger()), configfile.WithNotifyEngine(engine))
+		if err != nil {
+			return exitError, err
+		}
+		go r.Run(ctx)
+
+		// NOTE: an explicit -interval takes precedence over the config file's
+		// session settings.
+		if !flagSet(fs, "interval") {
+			sessionSettings = r.File().Session
+		}
+	}
+
+	for _, handle := range handles {
+		if _, err := cr.Track(ctx, handle); err != nil {
+			return exitError, fmt.Errorf("Track: %w", err)
+		}
+	}
+
+	l := cr.Listen()
+	defer l.Discard()
+
+	if err := cr.Start(ctx, sessionSettings); err != nil {
+		return exitError, fmt.Errorf("Start: %w", err)
+	}
+	defer cr.Stop(context.Background())
+
+	var tracker cyoutube.StateTracker
+	enc := json.NewEncoder(os.Stdout)
+
+	ch := l.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return exitOK, nil
+
+		case result, ok := <-ch:
+			if !ok {
+				return exitOK, nil
+			}
+
+			for _, event := range tracker.Update(result) {
+				if err := enc.Encode(event); err != nil {
+					return exitError, err
+				}
+
+				if engine != nil {
+					// NOTE: sink errors are logged by Dispatch.
+					engine.Dispatch(ctx, event)
+				}
+			}
+		}
+	}
+}
+
+// Reads events (as printed by watch, one per line) from stdin, and prints the
+// notification rules of the config file that each of them matches, without
+// notifying anything.
+func runNotify(ctx context.Context, args []string) (int, error) {
+	fs := flag.NewFlagSet("notify", flag.ExitOnError)
+	configPath := fs.String("config", "", "config file (notification rules and handle tags)")
+	fs.Parse(args)
+
+	if *configPath == "" {
+		return exitError, errors.New("missing -config")
+	}
+
+	file, err := configfile.Load(*configPath)
+	if err != nil {
+		return exitError, err
+	}
+
+	var rules notify.Rules
+	if file.Notify != nil {
+		rules = *file.Notify
+	}
+
+	engine, err := notify.NewEngine(nil, notify.WithRules(rules))
+	if err != nil {
+		return exitError, err
+	}
+	engine.SetTags(file.Tags())
+
+	dec := json.NewDecoder(os.Stdin)
+	for {
+		if err := ctx.Err(); err != nil {
+			return exitError, err
+		}
+
+		var event cyoutube.Event
+		if err := dec.Decode(&event); err != nil {
+			if err == io.EOF {
+				return exitOK, nil
+			}
+
+			return exitError, err
+		}
+
+		out := struct {
+			Kind    cyoutube.EventKind `json:"kind"`
+			Handle  cyoutube.Handle    `json:"handle"`
+			Matches []notify.Match     `json:"matches"`
+		}{
+			Kind:    event.Kind,
+			Handle:  event.State.Handle,
+			Matches: engine.Evaluate(event),
+		}
+		if err := printJSON(out); err != nil {
+			return exitError, err
+		}
+	}
+}
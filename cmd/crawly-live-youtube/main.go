@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/youtube/v3"
+
+	cyoutube "github.com/rubpy/crawly-live-youtube"
+	"github.com/rubpy/crawly-live-youtube/proxypool"
+)
+
+//////////////////////////////////////////////////
+
+const (
+	exitOK      = 0
+	exitNotLive = 1
+	exitError   = 2
+)
+
+// Name of the environment variable holding the YouTube Data API v3 key.
+const apiKeyEnv = "YOUTUBE_API_KEY"
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, args []string) (exitCode int, err error)
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"resolve", "<channel URL | @handle>", runResolve},
+		{"check", "[flags] <handle>", runCheck},
+		{"feed", "<handle>", runFeed},
+		{"watch", "[flags] [handles...]", runWatch},
+		{"notify", "-config <file> < events", runNotify},
+	}
+}
+
+func main() {
+	flag.Usage = printUsage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		printUsage()
+		os.Exit(exitError)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+
+		exitCode, err := cmd.run(ctx, args[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.name, err)
+		}
+
+		stop()
+		os.Exit(exitCode)
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command: %q\n\n", args[0])
+	printUsage()
+	os.Exit(exitError)
+}
+
+func printUsage() {
+	w := flag.CommandLine.Output()
+
+	fmt.Fprintf(w, "Usage: %s <command> [arguments]\n\n", os.Args[0])
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s %s\n", cmd.name, cmd.usage)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Handles are channel IDs, channel URLs or @handles. The YouTube Data API\n")
+	fmt.Fprintf(w, "key is read from the -api-key flag or the %s environment variable.\n", apiKeyEnv)
+	fmt.Fprintf(w, "check exits with %d if the channel is live, %d if it is not, and %d on error.\n", exitOK, exitNotLive, exitError)
+}
+
+//////////////////////////////////////////////////
+
+type commonFlags struct {
+	apiKey  string
+	proxies stringList
+	verbose bool
+}
+
+func (cf *commonFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&cf.apiKey, "api-key", "", "YouTube Data API v3 key (defaults to $"+apiKeyEnv+")")
+	fs.Var(&cf.proxies, "proxy", "proxy URL for scraping requests (repeatable; rotated between)")
+	fs.BoolVar(&cf.verbose, "v", false, "log crawler activity to stderr")
+}
+
+// Flag value which may be given multiple times.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+var MissingAPIKey = errors.New("YouTube API key is empty (use -api-key or $" + apiKeyEnv + ")")
+
+// Builds a crawler; if requireAPIKey is false, the crawler is given an
+// unauthenticated service (sufficient for scraping-only commands).
+func (cf *commonFlags) newCrawler(ctx context.Context, requireAPIKey bool, opts ...cyoutube.ConfigOption) (*cyoutube.Crawler, error) {
+	apiKey := cf.apiKey
+	if apiKey == "" {
+		apiKey = os.Getenv(apiKeyEnv)
+	}
+
+	var svcOpt option.ClientOption
+	if apiKey != "" {
+		svcOpt = option.WithAPIKey(apiKey)
+	} else if requireAPIKey {
+		return nil, MissingAPIKey
+	} else {
+		svcOpt = option.WithoutAuthentication()
+	}
+
+	srv, err := youtube.NewService(ctx, svcOpt)
+	if err != nil {
+		return nil, fmt.Errorf("youtube.NewService: %w", err)
+	}
+
+	var logOutput io.Writer = io.Discard
+	if cf.verbose {
+		logOutput = os.Stderr
+	}
+	logger := slog.New(slog.NewTextHandler(logOutput, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	}))
+
+	opts = append([]cyoutube.ConfigOption{
+		cyoutube.WithLogger(logger),
+		cyoutube.WithService(srv),
+	}, opts...)
+
+	if len(cf.proxies) > 0 {
+		pool, err := proxypool.NewPool(
+			proxypool.WithLogger(logger.WithGroup("proxypool")),
+			proxypool.WithProxies(cf.proxies...),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("proxypool.NewPool: %w", err)
+		}
+
+		opts = append(opts, cyoutube.WithClient(pool))
+	}
+
+	cr, err := cyoutube.NewCrawler(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("cyoutube.NewCrawler: %w", err)
+	}
+
+	return cr, nil
+}
+
+func parseHandleArg(fs *flag.FlagSet, n int) (handles []cyoutube.Handle, err error) {
+	args := fs.Args()
+	if (n > 0 && len(args) != n) || len(args) == 0 {
+		fs.Usage()
+		return nil, errors.New("wrong number of arguments")
+	}
+
+	for _, arg := range args {
+		handle, err := cyoutube.ParseHandle(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", arg, err)
+		}
+
+		handles = append(handles, handle)
+	}
+
+	return
+}
+
+func flagSet(fs *flag.FlagSet, name string) (set bool) {
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+
+	return
+}
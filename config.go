@@ -16,11 +16,16 @@ type config struct {
 	logger  *slog.Logger
 	client  cclient.Client
 	service *youtube.Service
+	clock   Clock
 
 	settings struct {
 		v  CrawlerSettings
 		ok bool
 	}
+	requestProfile struct {
+		v  RequestProfile
+		ok bool
+	}
 }
 
 var (
@@ -38,6 +43,18 @@ func validateConfig(cfg *config) error {
 		return NilService
 	}
 
+	if cfg.settings.ok {
+		if err := cfg.settings.v.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if cfg.requestProfile.ok {
+		if err := cfg.requestProfile.v.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -65,9 +82,15 @@ func buildCrawlerFromConfig(cfg *config) (cr *Crawler, err error) {
 		return nil, NilService
 	}
 
+	clock := cfg.clock
+	if clock == nil {
+		clock = SystemClock
+	}
+
 	cr = &Crawler{
 		client:  cl,
 		service: svc,
+		clock:   clock,
 	}
 
 	cr.Crawler.SetLogger(cfg.logger)
@@ -77,9 +100,15 @@ func buildCrawlerFromConfig(cfg *config) (cr *Crawler, err error) {
 	})
 
 	if cfg.settings.ok {
-		cr.SetSettings(cfg.settings.v)
+		cr.setSettings(cfg.settings.v)
 	} else {
-		cr.SetSettings(DefaultSettings)
+		cr.setSettings(DefaultSettings)
+	}
+
+	if cfg.requestProfile.ok {
+		cr.storeRequestProfile(cfg.requestProfile.v)
+	} else {
+		cr.storeRequestProfile(DefaultRequestProfile)
 	}
 
 	return cr, nil
@@ -113,3 +142,16 @@ func WithSettings(settings CrawlerSettings) ConfigOption {
 		cfg.settings.ok = true
 	}
 }
+
+func WithClock(clock Clock) ConfigOption {
+	return func(cfg *config) {
+		cfg.clock = clock
+	}
+}
+
+func WithRequestProfile(profile RequestProfile) ConfigOption {
+	return func(cfg *config) {
+		cfg.requestProfile.v = profile
+		cfg.requestProfile.ok = true
+	}
+}
@@ -0,0 +1,147 @@
+package configfile
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+//////////////////////////////////////////////////
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Walks a generic JSON value (as decoded into `any`) alongside the Go type it
+// is eventually going to be decoded into, and replaces every string found in
+// place of a time.Duration with its integer representation (so that settings
+// can be written as, e.g., "45s" instead of 45000000000).
+func normalizeDurations(v any, t reflect.Type, path string) (any, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		s, ok := v.(string)
+		if !ok {
+			return v, nil
+		}
+
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		return json.Number(fmt.Sprint(int64(d))), nil
+	}
+
+	if pt := reflect.PointerTo(t); pt.Implements(jsonUnmarshalerType) || pt.Implements(textUnmarshalerType) {
+		return v, nil
+	}
+
+	var err error
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]any)
+		if !ok {
+			break
+		}
+
+		fields := jsonFields(t)
+		for k, vv := range m {
+			ft, ok := fields[k]
+			if !ok {
+				for name, f := range fields {
+					if strings.EqualFold(name, k) {
+						ft, ok = f, true
+						break
+					}
+				}
+			}
+			if !ok {
+				continue
+			}
+
+			if m[k], err = normalizeDurations(vv, ft, path+"."+k); err != nil {
+				return nil, err
+			}
+		}
+
+	case reflect.Map:
+		m, ok := v.(map[string]any)
+		if !ok {
+			break
+		}
+
+		for k, vv := range m {
+			if m[k], err = normalizeDurations(vv, t.Elem(), path+"."+k); err != nil {
+				return nil, err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		a, ok := v.([]any)
+		if !ok {
+			break
+		}
+
+		for i, vv := range a {
+			if a[i], err = normalizeDurations(vv, t.Elem(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return v, nil
+}
+
+// Returns the types of all fields of a struct, keyed by their JSON names
+// (including fields promoted from embedded structs).
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				for k, v := range jsonFields(ft) {
+					if _, exists := fields[k]; !exists {
+						fields[k] = v
+					}
+				}
+
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fields[name] = f.Type
+	}
+
+	return fields
+}
@@ -0,0 +1,304 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/rubpy/crawly"
+	youtube "github.com/rubpy/crawly-live-youtube"
+	"github.com/rubpy/crawly-live-youtube/notify"
+)
+
+//////////////////////////////////////////////////
+
+// Describes everything needed to run a crawler: API keys, crawler and session
+// settings, the list of tracked handles and (optionally) notification rules.
+//
+// Files are written in JSON, YAML or TOML (see FormatOf), with the same keys
+// in each; durations may be given either as strings accepted by
+// time.ParseDuration (e.g., "45s") or as integer nanoseconds. Omitted settings
+// keep their default values.
+type File struct {
+	APIKey string `json:"api_key"`
+
+	Session crawly.SessionSettings  `json:"session"`
+	Crawler youtube.CrawlerSettings `json:"crawler"`
+
+	RequestProfile youtube.RequestProfile `json:"request_profile"`
+
+	Handles []HandleEntry `json:"handles"`
+
+	Notify *notify.Rules `json:"notify,omitempty"`
+}
+
+var DefaultSessionSettings = crawly.SessionSettings{
+	Interval:          30 * time.Second,
+	SinglePassTimeout: 45 * time.Second,
+}
+
+// A single entry of the handle list; written either as a string (a channel
+// ID, a channel URL, a channel "@handle", or a playlist ID or URL), or as an
+// object with a "handle" key alongside settings override fields and tags
+// (see notify.Condition), e.g.:
+//
+//	{"handle": "@LofiGirl", "tier": "fast", "minimum_check_video_delay": "10s", "tags": ["music"]}
+type HandleEntry struct {
+	Handle   youtube.Handle
+	Settings youtube.SettingsOverride
+	Tags     []string
+}
+
+type handleEntryObject struct {
+	Handle string   `json:"handle"`
+	Tags   []string `json:"tags,omitempty"`
+	youtube.SettingsOverride
+}
+
+func (e HandleEntry) MarshalJSON() ([]byte, error) {
+	if e.Settings.Empty() && len(e.Tags) == 0 {
+		return json.Marshal(e.Handle.Value)
+	}
+
+	return json.Marshal(handleEntryObject{
+		Handle:           e.Handle.Value,
+		Tags:             e.Tags,
+		SettingsOverride: e.Settings,
+	})
+}
+
+func (e *HandleEntry) UnmarshalJSON(b []byte) (err error) {
+	var s string
+
+	if b = bytes.TrimSpace(b); len(b) > 0 && b[0] == '{' {
+		var raw any
+		{
+			dec := json.NewDecoder(bytes.NewReader(b))
+			dec.UseNumber()
+
+			if err = dec.Decode(&raw); err != nil {
+				return
+			}
+		}
+
+		var obj handleEntryObject
+		if raw, err = normalizeDurations(raw, reflect.TypeOf(obj), "handle"); err != nil {
+			return
+		}
+		if b, err = json.Marshal(raw); err != nil {
+			return
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		if err = dec.Decode(&obj); err != nil {
+			return
+		}
+
+		s = obj.Handle
+		e.Settings = obj.SettingsOverride
+		e.Tags = obj.Tags
+	} else {
+		if err = json.Unmarshal(b, &s); err != nil {
+			return
+		}
+	}
+
+	e.Handle, err = youtube.ParseHandle(s)
+	if err != nil {
+		return fmt.Errorf("%q: %w", s, err)
+	}
+
+	return
+}
+
+//////////////////////////////////////////////////
+
+type Format uint8
+
+const (
+	FormatJSON Format = iota
+	FormatYAML
+	FormatTOML
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatJSON:
+		return "json"
+	case FormatYAML:
+		return "yaml"
+	case FormatTOML:
+		return "toml"
+	}
+
+	return ""
+}
+
+// Returns the format of a config file, by its extension (".yaml" or ".yml"
+// for YAML, ".toml" for TOML, and JSON otherwise).
+func FormatOf(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	}
+
+	return FormatJSON
+}
+
+//////////////////////////////////////////////////
+
+var (
+	EmptyFile       = errors.New("config file is empty")
+	DuplicateHandle = errors.New("duplicate handle")
+	InvalidFormat   = errors.New("invalid config file format")
+)
+
+func Load(path string) (*File, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := ParseFormat(b, FormatOf(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return f, nil
+}
+
+// Parses a JSON config file.
+func Parse(b []byte) (*File, error) {
+	return ParseFormat(b, FormatJSON)
+}
+
+func ParseFormat(b []byte, format Format) (*File, error) {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil, EmptyFile
+	}
+
+	f := &File{
+		Session: DefaultSessionSettings,
+		Crawler: youtube.DefaultSettings,
+
+		RequestProfile: youtube.DefaultRequestProfile,
+	}
+
+	raw, err := decodeRaw(b, format)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err = normalizeDurations(raw, reflect.TypeOf(f), "")
+	if err != nil {
+		return nil, err
+	}
+
+	nb, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(nb))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(f); err != nil {
+		return nil, err
+	}
+
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Decodes a config file into a generic JSON value (as decoded into `any`),
+// which is then decoded into a File the same way regardless of its format.
+func decodeRaw(b []byte, format Format) (raw any, err error) {
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+
+		err = dec.Decode(&raw)
+
+	case FormatYAML:
+		err = yaml.Unmarshal(b, &raw)
+
+	case FormatTOML:
+		var m map[string]any
+		if _, err = toml.Decode(string(b), &m); err == nil {
+			raw = m
+		}
+
+	default:
+		err = fmt.Errorf("%w: %d", InvalidFormat, format)
+	}
+
+	return
+}
+
+func (f *File) validate() error {
+	if err := f.Crawler.Validate(); err != nil {
+		return err
+	}
+
+	if err := f.RequestProfile.Validate(); err != nil {
+		return err
+	}
+
+	seen := map[youtube.Handle]struct{}{}
+	for _, e := range f.Handles {
+		if _, ok := seen[e.Handle]; ok {
+			return fmt.Errorf("%w: %s", DuplicateHandle, e.Handle)
+		}
+
+		seen[e.Handle] = struct{}{}
+
+		if err := e.Settings.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", e.Handle, err)
+		}
+
+		if e.Settings.Tier != "" {
+			if _, ok := f.Crawler.Tiers[e.Settings.Tier]; !ok {
+				return fmt.Errorf("%s: %w: %q", e.Handle, youtube.UnknownSettingsTier, e.Settings.Tier)
+			}
+		}
+	}
+
+	if f.Notify != nil {
+		if err := f.Notify.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the tags of every handle that has any.
+func (f *File) Tags() map[youtube.Handle][]string {
+	tags := map[youtube.Handle][]string{}
+	if f == nil {
+		return tags
+	}
+
+	for _, e := range f.Handles {
+		if len(e.Tags) > 0 {
+			tags[e.Handle] = e.Tags
+		}
+	}
+
+	return tags
+}
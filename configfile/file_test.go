@@ -0,0 +1,84 @@
+package configfile
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	youtube "github.com/rubpy/crawly-live-youtube"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		src    string
+	}{
+		{"json", FormatJSON, `{
+  "api_key": "key",
+  "session": {"interval": "1m"},
+  "crawler": {"minimum_check_video_delay": "10s", "tiers": {"fast": {"minimum_fetch_channel_feed_delay": "10s"}}},
+  "handles": ["@LofiGirl", {"handle": "UCSJ4gkVC6NrvII8umztf0Ow", "tier": "fast", "tags": ["music"]}]
+}`},
+		{"yaml", FormatYAML, `
+api_key: key
+session:
+  interval: 1m
+crawler:
+  minimum_check_video_delay: 10s
+  tiers:
+    fast: {minimum_fetch_channel_feed_delay: 10s}
+handles:
+  - "@LofiGirl"
+  - {handle: UCSJ4gkVC6NrvII8umztf0Ow, tier: fast, tags: [music]}
+`},
+		{"toml", FormatTOML, `
+api_key = "key"
+handles = ["@LofiGirl", {handle = "UCSJ4gkVC6NrvII8umztf0Ow", tier = "fast", tags = ["music"]}]
+
+[session]
+interval = "1m"
+
+[crawler]
+minimum_check_video_delay = "10s"
+tiers = {fast = {minimum_fetch_channel_feed_delay = "10s"}}
+`},
+	}
+
+	var want *File
+	for _, tt := range tests {
+		f, err := ParseFormat([]byte(tt.src), tt.format)
+		if err != nil {
+			t.Fatalf("%s: ParseFormat: %v", tt.name, err)
+		}
+
+		if f.APIKey != "key" || f.Session.Interval != time.Minute || f.Crawler.MinimumCheckVideoDelay != 10*time.Second {
+			t.Fatalf("%s: got %+v", tt.name, f)
+		}
+		if len(f.Handles) != 2 || f.Handles[1].Handle != youtube.ChannelID("UCSJ4gkVC6NrvII8umztf0Ow") || f.Handles[1].Settings.Tier != "fast" {
+			t.Fatalf("%s: got handles %+v", tt.name, f.Handles)
+		}
+
+		if want == nil {
+			want = f
+		} else if !reflect.DeepEqual(f, want) {
+			t.Fatalf("%s: got %+v, want %+v", tt.name, f, want)
+		}
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]Format{
+		"config.json":      FormatJSON,
+		"config":           FormatJSON,
+		"/etc/config.yaml": FormatYAML,
+		"config.YML":       FormatYAML,
+		"config.toml":      FormatTOML,
+	}
+
+	for path, want := range tests {
+		if got := FormatOf(path); got != want {
+			t.Errorf("FormatOf(%q) = %s, want %s", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package configfile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	youtube "github.com/rubpy/crawly-live-youtube"
+	"github.com/rubpy/crawly-live-youtube/notify"
+	"github.com/rubpy/crawly/clog"
+)
+
+//////////////////////////////////////////////////
+
+type config struct {
+	logger       *slog.Logger
+	pollInterval time.Duration
+	engine       *notify.Engine
+}
+
+var NilCrawler = errors.New("crawler is nil")
+
+// Default interval at which the config file's modification time is checked.
+var DefaultPollInterval = 5 * time.Second
+
+type ConfigOption func(cfg *config)
+
+func WithLogger(logger *slog.Logger) ConfigOption {
+	return func(cfg *config) {
+		cfg.logger = logger
+	}
+}
+
+// Sets the interval at which the config file is checked for modifications (a
+// negative value disables polling, leaving only SIGHUP).
+func WithPollInterval(interval time.Duration) ConfigOption {
+	return func(cfg *config) {
+		cfg.pollInterval = interval
+	}
+}
+
+// Keeps the rules of a notification engine (and the tags of handles) in sync
+// with the file's as well.
+func WithNotifyEngine(engine *notify.Engine) ConfigOption {
+	return func(cfg *config) {
+		cfg.engine = engine
+	}
+}
+
+//////////////////////////////////////////////////
+
+// Keeps a crawler in sync with a config file: handles added to or removed
+// from the file get tracked or untracked, and changed settings are applied
+// via SetSettings (or SetHandleSettings, for per-handle overrides) and
+// SetRequestProfile.
+type Reloader struct {
+	cr     *youtube.Crawler
+	path   string
+	logger *slog.Logger
+	engine *notify.Engine
+
+	pollInterval time.Duration
+
+	mu      sync.Mutex
+	file    *File
+	modTime time.Time
+	handles map[youtube.Handle]youtube.SettingsOverride
+}
+
+// Loads the config file at path and applies it to cr (i.e., the returned
+// Reloader has already tracked all of the listed handles).
+func NewReloader(ctx context.Context, cr *youtube.Crawler, path string, opts ...ConfigOption) (*Reloader, error) {
+	var cfg config
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cr == nil {
+		return nil, NilCrawler
+	}
+
+	r := &Reloader{
+		cr:     cr,
+		path:   path,
+		logger: cfg.logger,
+		engine: cfg.engine,
+
+		pollInterval: cfg.pollInterval,
+
+		handles: map[youtube.Handle]youtube.SettingsOverride{},
+	}
+	if r.pollInterval == 0 {
+		r.pollInterval = DefaultPollInterval
+	}
+
+	if _, err := r.Reload(ctx); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *Reloader) Log(ctx context.Context, params clog.Params) {
+	if r.logger == nil {
+		return
+	}
+
+	clog.WithParams(r.logger, ctx, params)
+}
+
+// Returns the most recently applied config file.
+func (r *Reloader) File() *File {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.file
+}
+
+// Re-reads the config file and applies the differences to the crawler. On
+// error, the previously applied config is left in place.
+func (r *Reloader) Reload(ctx context.Context) (changed bool, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lp := clog.Params{
+		Message: "reload",
+		Level:   slog.LevelInfo,
+
+		Values: clog.ParamGroup{
+			"path": r.path,
+		},
+	}
+	defer func() {
+		lp.Err = err
+		r.Log(ctx, lp)
+	}()
+
+	var modTime time.Time
+	if fi, serr := os.Stat(r.path); serr == nil {
+		modTime = fi.ModTime()
+	}
+
+	// NOTE: Load validates everything that is applied below (settings, request
+	// profile, per-handle overrides and notification rules), so that a broken
+	// file is rejected before any of it reaches the crawler.
+	f, err := Load(r.path)
+	if err != nil {
+		return
+	}
+
+	prev := r.file
+	if prev == nil || !reflect.DeepEqual(prev.Crawler, f.Crawler) {
+		if err = r.setSettings(f); err != nil {
+			return
+		}
+
+		lp.Set("settings", true)
+		changed = true
+	}
+	if prev == nil || !reflect.DeepEqual(prev.RequestProfile, f.RequestProfile) {
+		if err = r.cr.SetRequestProfile(f.RequestProfile); err != nil {
+			return
+		}
+
+		lp.Set("requestProfile", true)
+		changed = true
+	}
+	if r.engine != nil && (prev == nil || !reflect.DeepEqual(prev.Notify, f.Notify) || !reflect.DeepEqual(prev.Tags(), f.Tags())) {
+		var rules notify.Rules
+		if f.Notify != nil {
+			rules = *f.Notify
+		}
+
+		if err = r.engine.SetRules(rules); err != nil {
+			return
+		}
+		r.engine.SetTags(f.Tags())
+
+		lp.Set("notify", true)
+		changed = true
+	}
+	r.file = f
+
+	if prev != nil && prev.Session != f.Session {
+		// NOTE: session settings can only be applied by restarting the crawler,
+		// which would also discard all of its listeners.
+		r.Log(ctx, clog.Params{
+			Message: "reload: session settings changed; restart required",
+			Level:   slog.LevelWarn,
+		})
+	}
+	if prev != nil && prev.APIKey != f.APIKey {
+		// NOTE: the Data API service is created (with the key) along with the
+		// crawler.
+		r.Log(ctx, clog.Params{
+			Message: "reload: API key changed; restart required",
+			Level:   slog.LevelWarn,
+		})
+	}
+
+	handles := make(map[youtube.Handle]youtube.SettingsOverride, len(f.Handles))
+	for _, e := range f.Handles {
+		handles[e.Handle] = e.Settings
+	}
+
+	var tracked, untracked, updated []string
+	for handle, override := range handles {
+		if prevOverride, ok := r.handles[handle]; ok {
+			if reflect.DeepEqual(prevOverride, override) {
+				continue
+			}
+
+			if serr := r.cr.SetHandleSettings(handle, override); serr != nil {
+				err = errors.Join(err, serr)
+				handles[handle] = prevOverride
+				continue
+			}
+
+			updated = append(updated, handle.String())
+			continue
+		}
+
+		if _, terr := r.cr.TrackWithSettings(ctx, handle, override); terr != nil {
+			err = errors.Join(err, terr)
+			delete(handles, handle)
+			continue
+		}
+
+		tracked = append(tracked, handle.String())
+	}
+	for handle, prevOverride := range r.handles {
+		if _, ok := handles[handle]; ok {
+			continue
+		}
+
+		if _, uerr := r.cr.Untrack(ctx, handle); uerr != nil {
+			err = errors.Join(err, uerr)
+			handles[handle] = prevOverride
+			continue
+		}
+
+		untracked = append(untracked, handle.String())
+	}
+	r.handles = handles
+
+	// NOTE: the modification time is only recorded once the file has been
+	// applied in full, so that polling retries a file that was read
+	// half-written (or that could not be applied).
+	if err == nil {
+		r.modTime = modTime
+	}
+
+	if len(tracked) > 0 {
+		lp.Set("tracked", tracked)
+		changed = true
+	}
+	if len(untracked) > 0 {
+		lp.Set("untracked", untracked)
+		changed = true
+	}
+	if len(updated) > 0 {
+		lp.Set("updated", updated)
+		changed = true
+	}
+
+	return
+}
+
+// Applies the file's crawler settings. Handles whose current override refers
+// to a tier that the new settings drop are detached from it beforehand (their
+// new overrides are attached once the handles are diffed); if the settings
+// are rejected regardless, those overrides are restored.
+func (r *Reloader) setSettings(f *File) (err error) {
+	detached := map[youtube.Handle]youtube.SettingsOverride{}
+	for handle, override := range r.handles {
+		if override.Tier == "" {
+			continue
+		}
+		if _, ok := f.Crawler.Tiers[override.Tier]; ok {
+			continue
+		}
+
+		if err = r.cr.SetHandleSettings(handle, youtube.SettingsOverride{}); err != nil {
+			break
+		}
+
+		detached[handle] = override
+		r.handles[handle] = youtube.SettingsOverride{}
+	}
+
+	if err == nil {
+		err = r.cr.SetSettings(f.Crawler)
+	}
+	if err != nil {
+		for handle, override := range detached {
+			_ = r.cr.SetHandleSettings(handle, override)
+			r.handles[handle] = override
+		}
+	}
+
+	return
+}
+
+// Reloads the config file whenever the process receives SIGHUP or the file's
+// modification time changes, until ctx is done.
+func (r *Reloader) Run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var poll <-chan time.Time
+	if r.pollInterval > 0 {
+		ticker := time.NewTicker(r.pollInterval)
+		defer ticker.Stop()
+
+		poll = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-hup:
+			_, _ = r.Reload(ctx)
+
+		case <-poll:
+			fi, err := os.Stat(r.path)
+			if err != nil {
+				continue
+			}
+
+			r.mu.Lock()
+			modified := !fi.ModTime().Equal(r.modTime)
+			r.mu.Unlock()
+
+			if modified {
+				_, _ = r.Reload(ctx)
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package configfile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	youtube "github.com/rubpy/crawly-live-youtube"
+	"github.com/rubpy/crawly-live-youtube/youtubetest"
+)
+
+func TestReload(t *testing.T) {
+	ctx := context.Background()
+
+	srv := youtubetest.NewServer()
+	defer srv.Close()
+
+	cr, err := srv.NewCrawler(ctx)
+	if err != nil {
+		t.Fatalf("NewCrawler: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	mtime := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	write := func(src string) {
+		t.Helper()
+
+		mtime = mtime.Add(time.Minute)
+		if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	handle := youtube.ChannelID("UCSJ4gkVC6NrvII8umztf0Ow")
+	checkDelay := func(want time.Duration) {
+		t.Helper()
+
+		if got := cr.EffectiveHandleSettings(handle).MinimumCheckVideoDelay; got != want {
+			t.Fatalf("got MinimumCheckVideoDelay %s, want %s", got, want)
+		}
+	}
+
+	write(`{
+  "crawler": {"tiers": {"fast": {"minimum_check_video_delay": "10s"}}},
+  "handles": [{"handle": "UCSJ4gkVC6NrvII8umztf0Ow", "tier": "fast"}]
+}`)
+	r, err := NewReloader(ctx, cr, path, WithPollInterval(-1))
+	if err != nil {
+		t.Fatalf("NewReloader: %v", err)
+	}
+	checkDelay(10 * time.Second)
+	if !r.modTime.Equal(mtime) {
+		t.Fatalf("got modTime %s, want %s", r.modTime, mtime)
+	}
+
+	// NOTE: the settings are valid, but the rules are not; nothing is applied.
+	applied := mtime
+	write(`{
+  "crawler": {"minimum_check_video_delay": "40s", "tiers": {"fast": {"minimum_check_video_delay": "10s"}}},
+  "handles": [{"handle": "UCSJ4gkVC6NrvII8umztf0Ow", "tier": "fast"}],
+  "notify": {"rules": [{"sinks": ["missing"]}]}
+}`)
+	if _, err := r.Reload(ctx); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if got := cr.Settings().MinimumCheckVideoDelay; got == 40*time.Second {
+		t.Fatalf("settings applied despite the error")
+	}
+	if !r.modTime.Equal(applied) {
+		t.Fatalf("modTime recorded despite the error")
+	}
+
+	// Renaming a tier along with the handles that refer to it.
+	write(`{
+  "crawler": {"tiers": {"quick": {"minimum_check_video_delay": "20s"}}},
+  "handles": [{"handle": "UCSJ4gkVC6NrvII8umztf0Ow", "tier": "quick"}]
+}`)
+	if _, err := r.Reload(ctx); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	checkDelay(20 * time.Second)
+	if !r.modTime.Equal(mtime) {
+		t.Fatalf("got modTime %s, want %s", r.modTime, mtime)
+	}
+}
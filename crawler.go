@@ -1,6 +1,9 @@
 package youtube
 
 import (
+	"regexp"
+	"sync"
+
 	"google.golang.org/api/youtube/v3"
 
 	"github.com/rubpy/crawly"
@@ -15,10 +18,28 @@ type Crawler struct {
 
 	client  cclient.Client
 	service *youtube.Service
+	clock   Clock
+
+	channelIDCache   csync.Map[string, string]
+	channelInfoCache csync.Map[string, ChannelInfo]
+	overrides        csync.Map[Handle, handleSettings]
+	circuits         circuitBreakers
+	failureResets    csync.Map[Handle, struct{}]
+
+	// Failed refreshes of cached channel metadata (see refreshChannelInfo).
+	channelInfoFailures csync.Map[string, FailureState]
+
+	uploadsPlaylistCache csync.Map[string, string]
+	quota                quotaTracker
 
-	channelIDCache csync.Map[string, string]
+	filterPatterns csync.Map[string, *regexp.Regexp]
 
-	settings csync.Value[CrawlerSettings]
+	// Serializes changes to settings and overrides, so that the effective
+	// settings of a handle are never computed from stale crawler settings.
+	settingsMu        sync.Mutex
+	settings          csync.Value[CrawlerSettings]
+	effectiveSettings csync.Value[CrawlerSettings]
+	requestProfile    csync.Value[RequestProfile]
 }
 
 func NewCrawler(opts ...ConfigOption) (*Crawler, error) {
@@ -17,14 +17,32 @@ import (
 type EntityData struct {
 	Live       bool     `json:"live"`
 	LiveVideos []string `json:"live_videos"`
+	// Owning channel (ID) of each live video (which, unless the handle is a
+	// playlist, is always the tracked channel).
+	LiveVideoChannels map[string]string `json:"live_video_channels,omitempty"`
+	// Details of each live video (in the same order as LiveVideos), followed
+	// by those of the upcoming ones.
+	Streams []LiveVideo `json:"streams,omitempty"`
+	// Live (and upcoming) videos left out by the handle's StreamFilter (or
+	// ExcludePremieres).
+	FilteredVideos []string `json:"filtered_videos,omitempty"`
 
 	Feed          *xmlapi.ChannelFeed `json:"feed"`
 	LastFeedFetch time.Time           `json:"last_feed_fetch"`
 
 	FeedVideoCandidates []VideoCandidate `json:"feed_video_candidates"`
+
+	Schedule StreamSchedule `json:"schedule"`
+
+	// Set once the channel is found to no longer exist (see GonePolicy).
+	Gone       bool       `json:"gone"`
+	GoneReason GoneReason `json:"gone_reason"`
+	GoneSince  time.Time  `json:"gone_since"`
+
+	FailureState
 }
 
-func (cr *Crawler) entityHandler(ctx context.Context, entity *crawly.Entity, result *crawly.TrackingResult) error {
+func (cr *Crawler) entityHandler(ctx context.Context, entity *crawly.Entity, result *crawly.TrackingResult) (err error) {
 	handle, ok := entity.Handle.(Handle)
 	if !ok || !handle.Valid() {
 		return crawly.InvalidHandle
@@ -35,32 +53,86 @@ func (cr *Crawler) entityHandler(ctx context.Context, entity *crawly.Entity, res
 		entity.Data = data
 	}()
 
-	if handle.Type != HandleChannelID {
+	if handle.Type != HandleChannelID && handle.Type != HandlePlaylistID {
 		return crawly.InvalidHandle
 	}
+	playlist := handle.Type == HandlePlaylistID
 
-	settings := cr.loadSettings()
-	max := func(min time.Duration, v time.Duration) time.Duration {
-		if v < min {
-			return min
-		}
+	// NOTE: the key under which the handle is logged.
+	handleKey := "channelID"
+	if playlist {
+		handleKey = "playlistID"
+	}
+
+	settings := cr.loadHandleSettings(handle)
+
+	if cr.applyFailureReset(handle, &data) {
+		cr.Log(ctx, clog.Params{
+			Message: "resetFailures",
+			Level:   slog.LevelInfo,
 
-		return v
+			Values: clog.ParamGroup{
+				handleKey: handle.Value,
+			},
+		})
 	}
 
+	if !data.Ready(cr.now()) {
+		return nil
+	}
+	defer func() {
+		if err == nil {
+			data.FailureState.succeed()
+			return
+		}
+
+		// NOTE: crawly removes the entity (once this handler returns) after
+		// MaximumTrackingAttempts consecutive errors.
+		if limit := settings.MaximumTrackingAttempts; limit > 0 && entity.Attempt+1 >= limit {
+			cr.forget(handle)
+		}
+
+		if data.fail(err, cr.now(), settings) {
+			cr.Log(ctx, clog.Params{
+				Message: "parked",
+				Level:   slog.LevelWarn,
+				Err:     err,
+
+				Values: clog.ParamGroup{
+					handleKey:  handle.Value,
+					"failures": data.Failures,
+				},
+			})
+		}
+	}()
+
 	stopAfterLiveVideos := settings.StopAfterLiveVideos
-	if stopAfterLiveVideos < 1 {
-		stopAfterLiveVideos = 0
+	minimumFetchChannelFeedDelay, minimumCheckVideoDelay := settings.adaptiveDelays(&data.Schedule, cr.now(), data.Live)
+	maximumCachedNotLivestreamAge := settings.MaximumCachedNotLivestreamAge
+	maximumCachedLivestreamFinishedAge := settings.MaximumCachedLivestreamFinishedAge
+	maximumVideoAge := settings.MaximumVideoAge
+	checkVideoTimeout := settings.CheckVideoTimeout
+
+	if data.Gone && settings.GonePolicy == GonePolicyDowngrade {
+		minimumFetchChannelFeedDelay = max(minimumFetchChannelFeedDelay, settings.GoneFetchChannelFeedDelay)
 	}
 
-	minimumFetchChannelFeedDelay := max(1*time.Second, settings.MinimumFetchChannelFeedDelay)
-	maximumCachedNotLivestreamAge := max(1*time.Second, settings.MaximumCachedNotLivestreamAge)
-	maximumCachedLivestreamFinishedAge := max(1*time.Second, settings.MaximumCachedLivestreamFinishedAge)
-	minimumCheckVideoDelay := max(1*time.Second, settings.MinimumCheckVideoDelay)
-	maximumVideoAge := max(0*time.Second, settings.MaximumVideoAge)
-	checkVideoTimeout := max(0*time.Second, settings.CheckVideoTimeout)
+	// NOTE: leaves out videos from the future, and those older than
+	// maximumVideoAge.
+	acceptVideo := func(lastTouch int64) bool {
+		nowm := cr.now().UnixMilli()
+
+		if lastTouch >= nowm {
+			return false
+		}
+		if (nowm - lastTouch) > maximumVideoAge.Milliseconds() {
+			return false
+		}
 
-	getVideoCandidates := func(feed *xmlapi.ChannelFeed, channelID string) (vcs []VideoCandidate, err error) {
+		return true
+	}
+
+	getVideoCandidates := func(feed *xmlapi.ChannelFeed) (vcs []VideoCandidate, err error) {
 		if feed == nil {
 			err = errors.New("feed is nil")
 			return
@@ -71,24 +143,49 @@ func (cr *Crawler) entityHandler(ctx context.Context, entity *crawly.Entity, res
 			return
 		}
 
-		now := time.Now()
-		nowm := now.UnixMilli()
 		for _, vid := range vids {
 			lastTouch := vid.Updated
 			if vid.Published > lastTouch {
 				lastTouch = vid.Published
 			}
 
-			if lastTouch >= nowm {
+			if !acceptVideo(lastTouch) {
 				continue
 			}
-			if (nowm - lastTouch) > maximumVideoAge.Milliseconds() {
+
+			channelID := vid.ChannelID
+			if channelID == "" && !playlist {
+				channelID = handle.Value
+			}
+
+			vcs = append(vcs, VideoCandidate{
+				ID:           vid.ID,
+				ChannelID:    channelID,
+				Title:        vid.Title,
+				Description:  vid.Description,
+				ThumbnailURL: vid.ThumbnailURL,
+			})
+		}
+
+		return
+	}
+
+	getUploadCandidates := func(pctx context.Context) (vcs []VideoCandidate, err error) {
+		uploads, err := cr.fetchUploadsWithinBudget(pctx, handle.Value, settings)
+		if err != nil {
+			return
+		}
+
+		for _, upload := range uploads {
+			// NOTE: uploads without a (known) publish time are kept, leaving
+			// it to the live check.
+			if !upload.Published.IsZero() && !acceptVideo(upload.Published.UnixMilli()) {
 				continue
 			}
 
 			vcs = append(vcs, VideoCandidate{
-				ID:        vid.ID,
-				ChannelID: channelID,
+				ID:        upload.VideoID,
+				ChannelID: handle.Value,
 			})
 		}
 
@@ -117,7 +214,7 @@ func (cr *Crawler) entityHandler(ctx context.Context, entity *crawly.Entity, res
 		}
 		defer cancel()
 
-		if time.Now().Sub(vc.LastNotLivestream) >= maximumCachedNotLivestreamAge {
+		if cr.now().Sub(vc.LastNotLivestream) >= maximumCachedNotLivestreamAge {
 			vc.NotLivestream = false
 
 			lp := clog.Params{
@@ -132,7 +229,7 @@ func (cr *Crawler) entityHandler(ctx context.Context, entity *crawly.Entity, res
 
 			thumbnailExists, err := cr.CheckLiveVideoThumbnail(ctx, vc.ID, "")
 			if err == nil {
-				vc.LastNotLivestream = time.Now()
+				vc.LastNotLivestream = cr.now()
 				vc.NotLivestream = !thumbnailExists
 
 				lp.Set("thumbnailExists", thumbnailExists)
@@ -157,7 +254,7 @@ func (cr *Crawler) entityHandler(ctx context.Context, entity *crawly.Entity, res
 			return nil
 		}
 
-		if vc.LivestreamFinished && time.Now().Sub(vc.LastLivestreamFinished) <= maximumCachedLivestreamFinishedAge {
+		if vc.LivestreamFinished && cr.now().Sub(vc.LastLivestreamFinished) <= maximumCachedLivestreamFinishedAge {
 			vc.Live = false
 			vc.LiveCheckAttempt = 0
 
@@ -175,15 +272,30 @@ func (cr *Crawler) entityHandler(ctx context.Context, entity *crawly.Entity, res
 				},
 			}
 
-			live, finished, err := cr.CheckLiveVideoState(ctx, vc.ID)
-			vc.LastLive = time.Now()
+			state, err := cr.CheckLiveVideoDetails(ctx, vc.ID)
+			vc.LastLive = cr.now()
 			if err == nil {
-				vc.LivestreamFinished = finished
-				vc.LastLivestreamFinished = time.Now()
-				vc.Live = live
+				vc.LivestreamFinished = state.Finished
+				vc.LastLivestreamFinished = cr.now()
+				vc.Live = state.Live
+				vc.ActualStartTime = state.ActualStartTime
+				vc.ConcurrentViewers = state.ConcurrentViewers
+				vc.Premiere = state.Premiere
+				if !state.ScheduledStartTime.IsZero() {
+					vc.ScheduledStartTime = state.ScheduledStartTime
+				}
+				// NOTE: candidates from some sources (e.g., the "Live" tab)
+				// come without a description, which filters may need.
+				if vc.Title == "" {
+					vc.Title = state.Title
+				}
+				if vc.Description == "" {
+					vc.Description = state.Description
+				}
 
-				lp.Set("live", live)
-				lp.Set("finished", finished)
+				lp.Set("live", state.Live)
+				lp.Set("finished", state.Finished)
+				lp.Set("premiere", state.Premiere)
 			} else {
 				err = fmt.Errorf("CheckLiveVideoState: %w", err)
 
@@ -202,71 +314,263 @@ func (cr *Crawler) entityHandler(ctx context.Context, entity *crawly.Entity, res
 	}
 
 	{
-		channelID := handle.Value
 		data.Live = false
 
-		if (data.Feed == nil || len(data.FeedVideoCandidates) == 0) && time.Now().Sub(data.LastFeedFetch) >= minimumFetchChannelFeedDelay {
+		useFeed := playlist || settings.CandidateSources.Has(SourceFeed)
+		useUploads := !playlist && settings.CandidateSources.Has(SourceUploads)
+		useStreams := !playlist && settings.CandidateSources.Has(SourceStreams)
+
+		// NOTE: with adaptive polling, candidates are also refreshed
+		// periodically (at the adaptive feed fetch delay), rather than only
+		// once there are none left.
+		refetch := (useFeed && data.Feed == nil) || len(data.FeedVideoCandidates) == 0 || settings.AdaptivePolling
+		refreshInfo := false
+		if refetch && cr.now().Sub(data.LastFeedFetch) >= minimumFetchChannelFeedDelay {
+			prevVideoCandidates := data.FeedVideoCandidates
+
 			data.Feed = nil
 			data.FeedVideoCandidates = nil
 			data.LiveVideos = []string{}
+			data.LiveVideoChannels = nil
+			data.Streams = nil
+			data.FilteredVideos = nil
+
+			var vcs []VideoCandidate
+			// NOTE: errors of the (optional) uploads and streams sources are
+			// only returned when none of the sources succeeded.
+			var sourceErrs []error
+			fetched := false
+			if useFeed {
+				lp := clog.Params{
+					Message: "fetchChannelXMLFeed",
+					Level:   slog.LevelDebug,
+
+					Values: clog.ParamGroup{
+						handleKey: handle.Value,
+					},
+				}
 
-			lp := clog.Params{
-				Message: "fetchChannelXMLFeed",
-				Level:   slog.LevelDebug,
+				var feed *xmlapi.ChannelFeed
+				var err error
+				if playlist {
+					lp.Message = "fetchPlaylistXMLFeed"
 
-				Values: clog.ParamGroup{
-					"channelID": channelID,
-				},
+					feed, err = cr.FetchPlaylistXMLFeed(ctx, handle.Value)
+					if err != nil {
+						err = fmt.Errorf("FetchPlaylistXMLFeed: %w", err)
+					}
+				} else {
+					feed, err = cr.FetchChannelXMLFeed(ctx, handle.Value)
+					if err != nil {
+						err = fmt.Errorf("FetchChannelXMLFeed: %w", err)
+					}
+				}
+
+				if err == nil {
+					data.Feed = feed
+					data.LastFeedFetch = cr.now()
+
+					if !playlist && data.Gone {
+						cr.markRestored(ctx, handle.Value, &data)
+					}
+				}
+
+				lp.Err = err
+				cr.Log(ctx, lp)
+
+				if err != nil {
+					var fe *FetchError
+					if playlist || !errors.As(err, &fe) || !fe.NotFound() {
+						return err
+					}
+
+					gone, gerr := cr.detectGone(ctx, handle.Value, &data)
+					if gerr != nil {
+						return errors.Join(err, gerr)
+					}
+					if !gone {
+						return err
+					}
+
+					if settings.GonePolicy == GonePolicyUntrack {
+						cr.forget(handle)
+						result.Entity.Action = crawly.TrackingActionRemove
+					}
+
+					return nil
+				}
+
+				vcs, err = getVideoCandidates(feed)
+				if err != nil {
+					return err
+				}
+				fetched = true
 			}
 
-			feed, err := cr.FetchChannelXMLFeed(ctx, channelID)
-			if err == nil {
-				data.Feed = feed
-				data.LastFeedFetch = time.Now()
-			} else {
-				err = fmt.Errorf("FetchChannelXMLFeed: %w", err)
+			if useUploads {
+				lp := clog.Params{
+					Message: "fetchUploads",
+					Level:   slog.LevelDebug,
+
+					Values: clog.ParamGroup{
+						handleKey: handle.Value,
+					},
+				}
+
+				uvcs, err := getUploadCandidates(ctx)
+				if err != nil {
+					err = fmt.Errorf("FetchUploads: %w", err)
+
+					if (useFeed || useStreams) && !errors.Is(err, QuotaExceeded) {
+						// NOTE: the other sources' candidates are used on their own.
+						lp.Level = slog.LevelWarn
+					}
+					sourceErrs = append(sourceErrs, err)
+				} else {
+					lp.Set("candidates", len(uvcs))
+
+					vcs = mergeVideoCandidates(vcs, uvcs)
+					fetched = true
+				}
+
+				lp.Err = err
+				cr.Log(ctx, lp)
 			}
 
-			lp.Err = err
-			cr.Log(ctx, lp)
+			if useStreams {
+				lp := clog.Params{
+					Message: "fetchChannelStreams",
+					Level:   slog.LevelDebug,
 
-			if err != nil {
-				return err
+					Values: clog.ParamGroup{
+						handleKey: handle.Value,
+					},
+				}
+
+				svcs, err := cr.streamVideoCandidates(ctx, handle.Value)
+				if err != nil {
+					err = fmt.Errorf("FetchChannelStreams: %w", err)
+
+					if useFeed || useUploads {
+						lp.Level = slog.LevelWarn
+					}
+					sourceErrs = append(sourceErrs, err)
+				} else {
+					lp.Set("candidates", len(svcs))
+
+					vcs = mergeVideoCandidates(vcs, svcs)
+					fetched = true
+				}
+
+				lp.Err = err
+				cr.Log(ctx, lp)
 			}
 
-			data.FeedVideoCandidates, err = getVideoCandidates(feed, channelID)
-			if err != nil {
-				return err
+			if !fetched {
+				return errors.Join(sourceErrs...)
 			}
+			data.LastFeedFetch = cr.now()
+
+			data.FeedVideoCandidates = keepVideoCandidates(vcs, prevVideoCandidates)
+
+			refreshInfo = !playlist
 		}
 
 		data.LiveVideos = []string{}
+		data.LiveVideoChannels = nil
+		data.Streams = nil
+		data.FilteredVideos = nil
+
+		var upcoming []LiveVideo
 		for idx := range data.FeedVideoCandidates {
 			vc := &data.FeedVideoCandidates[idx]
 
-			if time.Now().Sub(vc.LastProcess) >= minimumCheckVideoDelay {
+			if vc.Ready(cr.now()) && cr.now().Sub(vc.LastProcess) >= minimumCheckVideoDelay {
 				err := processVideoCandidate(ctx, vc)
-				vc.LastProcess = time.Now()
+				vc.LastProcess = cr.now()
 				if err == nil {
 					vc.LiveGenuine = true
+					vc.FailureState.succeed()
+
+					data.Schedule.Observe(vc.ID, vc.ActualStartTime)
 				} else {
 					vc.LiveGenuine = false
 
+					if vc.fail(err, cr.now(), settings) {
+						cr.Log(ctx, clog.Params{
+							Message: "parked",
+							Level:   slog.LevelWarn,
+							Err:     err,
+
+							Values: clog.ParamGroup{
+								"videoID":   vc.ID,
+								"channelID": vc.ChannelID,
+								"failures":  vc.Failures,
+							},
+						})
+					}
+
+					level := slog.LevelError
+					if errors.Is(err, CircuitOpen) {
+						// NOTE: already reported (once) when the circuit opened.
+						level = slog.LevelDebug
+					}
+
 					cr.Log(ctx, clog.Params{
 						Message: "processVideoCandidate",
+						Level:   level,
+						Err:     err,
+
+						Values: clog.ParamGroup{
+							"videoID":   vc.ID,
+							"channelID": vc.ChannelID,
+						},
+					})
+				}
+			}
+
+			if vc.Premiere && settings.ExcludePremieres {
+				if vc.kind() != LiveVideoNone {
+					data.FilteredVideos = append(data.FilteredVideos, vc.ID)
+				}
+				continue
+			}
+
+			if kind := vc.kind(); kind != LiveVideoNone && settings.Filter != nil {
+				pass, err := cr.passesFilter(settings.Filter, vc, kind)
+				if err != nil {
+					// NOTE: should never happen; filters are validated beforehand.
+					cr.Log(ctx, clog.Params{
+						Message: "passesFilter",
 						Level:   slog.LevelError,
 						Err:     err,
 
 						Values: clog.ParamGroup{
 							"videoID":   vc.ID,
-							"channelID": channelID,
+							"channelID": vc.ChannelID,
 						},
 					})
 				}
+
+				if !pass {
+					data.FilteredVideos = append(data.FilteredVideos, vc.ID)
+					continue
+				}
+			}
+
+			if vc.upcoming() {
+				upcoming = append(upcoming, liveVideoFromCandidate(vc, vc.kind()))
 			}
 
 			if vc.LiveGenuine && vc.Live {
 				data.LiveVideos = append(data.LiveVideos, vc.ID)
+				data.Streams = append(data.Streams, liveVideoFromCandidate(vc, vc.kind()))
+				if vc.ChannelID != "" {
+					if data.LiveVideoChannels == nil {
+						data.LiveVideoChannels = make(map[string]string)
+					}
+					data.LiveVideoChannels[vc.ID] = vc.ChannelID
+				}
 
 				if stopAfterLiveVideos > 0 && len(data.LiveVideos) >= stopAfterLiveVideos {
 					break
@@ -274,7 +578,14 @@ func (cr *Crawler) entityHandler(ctx context.Context, entity *crawly.Entity, res
 			}
 		}
 
+		data.Streams = append(data.Streams, upcoming...)
 		data.Live = len(data.LiveVideos) > 0
+
+		// NOTE: only once the live check is done, so that a slow channel page
+		// does not hold it up.
+		if refreshInfo {
+			cr.refreshChannelInfo(ctx, handle.Value, settings)
+		}
 	}
 
 	return nil
@@ -0,0 +1,221 @@
+package youtube_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rubpy/crawly"
+	youtube "github.com/rubpy/crawly-live-youtube"
+	"github.com/rubpy/crawly-live-youtube/youtubetest"
+)
+
+const testChannelID = "UCSJ4gkVC6NrvII8umztf0Ow"
+
+func newTestCrawler(t *testing.T, settings youtube.CrawlerSettings) (*youtubetest.Server, *youtubetest.Clock, *youtube.Crawler) {
+	t.Helper()
+
+	srv := youtubetest.NewServer()
+	t.Cleanup(srv.Close)
+
+	clk := youtubetest.NewClock(time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC))
+	srv.Now = clk.Now
+
+	cr, err := srv.NewCrawler(context.Background(), youtube.WithClock(clk), youtube.WithSettings(settings))
+	if err != nil {
+		t.Fatalf("NewCrawler: %v", err)
+	}
+
+	if err := srv.AddChannel(youtubetest.Channel{ID: testChannelID, Title: "Test"}); err != nil {
+		t.Fatalf("AddChannel: %v", err)
+	}
+
+	return srv, clk, cr
+}
+
+// Runs the entity handler once (as a crawler pass would), and returns the
+// entity's data.
+func runPass(t *testing.T, cr *youtube.Crawler, e *crawly.Entity) youtube.EntityData {
+	t.Helper()
+
+	if err := youtube.EntityHandler(cr, context.Background(), e, &crawly.TrackingResult{}); err != nil {
+		t.Fatalf("entityHandler: %v", err)
+	}
+
+	data, _ := e.Data.(youtube.EntityData)
+	return data
+}
+
+func TestEntityAdaptiveRefetch(t *testing.T) {
+	settings := youtube.DefaultSettings
+	settings.AdaptivePolling = true
+
+	srv, clk, cr := newTestCrawler(t, settings)
+	e := &crawly.Entity{Handle: youtube.ChannelID(testChannelID)}
+
+	if err := srv.UploadVideo(testChannelID, "aaaaaaaaaaa", "Upload"); err != nil {
+		t.Fatalf("UploadVideo: %v", err)
+	}
+	clk.Advance(time.Minute)
+
+	if data := runPass(t, cr, e); len(data.FeedVideoCandidates) != 1 || data.Live {
+		t.Fatalf("got %d candidates (live: %t), want 1 (not live)", len(data.FeedVideoCandidates), data.Live)
+	}
+
+	// NOTE: the feed is refetched despite the existing candidate.
+	if err := srv.StartStream(testChannelID, "bbbbbbbbbbb", "Stream"); err != nil {
+		t.Fatalf("StartStream: %v", err)
+	}
+	clk.Advance(settings.MinimumFetchChannelFeedDelay)
+
+	data := runPass(t, cr, e)
+	if !data.Live || len(data.LiveVideos) != 1 || data.LiveVideos[0] != "bbbbbbbbbbb" {
+		t.Fatalf("got live %t, LiveVideos %v; want the new stream", data.Live, data.LiveVideos)
+	}
+}
+
+func TestEntityLiveStream(t *testing.T) {
+	settings := youtube.DefaultSettings
+
+	srv, clk, cr := newTestCrawler(t, settings)
+	e := &crawly.Entity{Handle: youtube.ChannelID(testChannelID)}
+
+	if data := runPass(t, cr, e); data.Live {
+		t.Fatalf("live before any stream started")
+	}
+
+	if err := srv.StartStream(testChannelID, "aaaaaaaaaaa", "Stream"); err != nil {
+		t.Fatalf("StartStream: %v", err)
+	}
+	clk.Advance(settings.MinimumFetchChannelFeedDelay)
+
+	data := runPass(t, cr, e)
+	if !data.Live || len(data.LiveVideos) != 1 || data.LiveVideos[0] != "aaaaaaaaaaa" {
+		t.Fatalf("got live %t, LiveVideos %v; want the stream", data.Live, data.LiveVideos)
+	}
+	if len(data.Streams) != 1 || data.Streams[0].Kind != youtube.LiveVideoLive || data.Streams[0].Title != "Stream" {
+		t.Fatalf("got Streams %+v", data.Streams)
+	}
+
+	if err := srv.EndStream("aaaaaaaaaaa"); err != nil {
+		t.Fatalf("EndStream: %v", err)
+	}
+	clk.Advance(settings.MinimumCheckVideoDelay)
+
+	if data := runPass(t, cr, e); data.Live || len(data.LiveVideos) != 0 {
+		t.Fatalf("got live %t, LiveVideos %v after the stream ended", data.Live, data.LiveVideos)
+	}
+}
+
+func TestResetFailuresUntracked(t *testing.T) {
+	_, _, cr := newTestCrawler(t, youtube.DefaultSettings)
+
+	if cr.ResetFailures(youtube.ChannelID(testChannelID)) {
+		t.Fatalf("ResetFailures of an untracked handle = true, want false")
+	}
+}
+
+func TestEntityChannelInfoRefresh(t *testing.T) {
+	settings := youtube.DefaultSettings
+	settings.AdaptivePolling = true
+	settings.FailureBackoffBase = 10 * time.Minute
+	settings.FailureBackoffJitter = 0
+
+	srv, clk, cr := newTestCrawler(t, settings)
+	e := &crawly.Entity{Handle: youtube.ChannelID(testChannelID)}
+
+	profile := youtube.DefaultRequestProfile
+	profile.Language = "de-DE"
+	if err := cr.SetRequestProfile(profile); err != nil {
+		t.Fatalf("SetRequestProfile: %v", err)
+	}
+
+	var requests []string
+	srv.Handle("/channel/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Query().Get("hl"))
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	runPass(t, cr, e)
+	if len(requests) != 1 || requests[0] != "en" {
+		t.Fatalf("got channel page requests (hl) %v, want [en]", requests)
+	}
+
+	// NOTE: the feed is refetched, but the failed refresh is backed off.
+	clk.Advance(settings.MinimumFetchChannelFeedDelay)
+	runPass(t, cr, e)
+	if len(requests) != 1 {
+		t.Fatalf("got %d channel page requests within the backoff, want 1", len(requests))
+	}
+
+	clk.Advance(settings.FailureBackoffBase)
+	runPass(t, cr, e)
+	if len(requests) != 2 {
+		t.Fatalf("got %d channel page requests after the backoff, want 2", len(requests))
+	}
+}
+
+func TestEntityFilteredVideos(t *testing.T) {
+	settings := youtube.DefaultSettings
+	// NOTE: candidates from the "Live" tab come without a description.
+	settings.CandidateSources = youtube.SourceStreams
+	settings.Filter = &youtube.StreamFilter{IncludeDescription: "(?i)tournament"}
+	settings.ExcludePremieres = true
+	settings.StopAfterLiveVideos = 0
+
+	srv, clk, cr := newTestCrawler(t, settings)
+	e := &crawly.Entity{Handle: youtube.ChannelID(testChannelID)}
+
+	streams := []struct {
+		id          string
+		description string
+	}{
+		{"aaaaaaaaaaa", "Tournament finals"},
+		{"bbbbbbbbbbb", "24/7 radio"},
+	}
+	for _, s := range streams {
+		if err := srv.StartStream(testChannelID, s.id, "Stream"); err != nil {
+			t.Fatalf("StartStream: %v", err)
+		}
+		if err := srv.UpdateVideo(s.id, func(v *youtubetest.Video) { v.Description = s.description }); err != nil {
+			t.Fatalf("UpdateVideo: %v", err)
+		}
+	}
+	if err := srv.SchedulePremiere(testChannelID, "ccccccccccc", "Premiere", clk.Now().Add(time.Hour), 10*time.Minute); err != nil {
+		t.Fatalf("SchedulePremiere: %v", err)
+	}
+	if err := srv.UpdateVideo("ccccccccccc", func(v *youtubetest.Video) { v.Description = "Tournament trailer" }); err != nil {
+		t.Fatalf("UpdateVideo: %v", err)
+	}
+	clk.Advance(time.Minute)
+
+	data := runPass(t, cr, e)
+	if len(data.LiveVideos) != 1 || data.LiveVideos[0] != "aaaaaaaaaaa" {
+		t.Fatalf("got LiveVideos %v, want [aaaaaaaaaaa]", data.LiveVideos)
+	}
+	if len(data.FilteredVideos) != 2 || data.FilteredVideos[0] != "bbbbbbbbbbb" || data.FilteredVideos[1] != "ccccccccccc" {
+		t.Fatalf("got FilteredVideos %v, want [bbbbbbbbbbb ccccccccccc]", data.FilteredVideos)
+	}
+}
+
+func TestEntityUploadsUnknownPublishTime(t *testing.T) {
+	settings := youtube.DefaultSettings
+	settings.CandidateSources = youtube.SourceUploads
+
+	srv, clk, cr := newTestCrawler(t, settings)
+	e := &crawly.Entity{Handle: youtube.ChannelID(testChannelID)}
+
+	if err := srv.StartStream(testChannelID, "aaaaaaaaaaa", "Stream"); err != nil {
+		t.Fatalf("StartStream: %v", err)
+	}
+	if err := srv.UpdateVideo("aaaaaaaaaaa", func(v *youtubetest.Video) { v.Published = time.Time{} }); err != nil {
+		t.Fatalf("UpdateVideo: %v", err)
+	}
+	clk.Advance(time.Minute)
+
+	data := runPass(t, cr, e)
+	if len(data.LiveVideos) != 1 || data.LiveVideos[0] != "aaaaaaaaaaa" {
+		t.Fatalf("got LiveVideos %v, want [aaaaaaaaaaa]", data.LiveVideos)
+	}
+}
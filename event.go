@@ -0,0 +1,342 @@
+package youtube
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rubpy/crawly"
+	"github.com/rubpy/crawly/csync"
+)
+
+//////////////////////////////////////////////////
+
+type LiveState struct {
+	Handle     Handle    `json:"handle"`
+	Live       bool      `json:"live"`
+	LiveVideos []string  `json:"live_videos"`
+	Timestamp  time.Time `json:"timestamp"`
+
+	// Owning channel (ID) of each live video.
+	LiveVideoChannels map[string]string `json:"live_video_channels,omitempty"`
+	// Details of each live (and upcoming) video.
+	Streams []LiveVideo `json:"streams,omitempty"`
+
+	Gone       bool       `json:"gone,omitempty"`
+	GoneReason GoneReason `json:"gone_reason,omitempty"`
+}
+
+func liveStateFromEntityData(handle Handle, data EntityData, timestamp time.Time) LiveState {
+	liveVideos := make([]string, len(data.LiveVideos))
+	copy(liveVideos, data.LiveVideos)
+
+	var liveVideoChannels map[string]string
+	if len(data.LiveVideoChannels) > 0 {
+		liveVideoChannels = make(map[string]string, len(data.LiveVideoChannels))
+		for videoID, channelID := range data.LiveVideoChannels {
+			liveVideoChannels[videoID] = channelID
+		}
+	}
+
+	var streams []LiveVideo
+	if len(data.Streams) > 0 {
+		streams = make([]LiveVideo, len(data.Streams))
+		copy(streams, data.Streams)
+	}
+
+	return LiveState{
+		Handle:     handle,
+		Live:       data.Live,
+		LiveVideos: liveVideos,
+		Timestamp:  timestamp,
+
+		LiveVideoChannels: liveVideoChannels,
+		Streams:           streams,
+
+		Gone:       data.Gone,
+		GoneReason: data.GoneReason,
+	}
+}
+
+func (s LiveState) sameLiveVideos(other LiveState) bool {
+	if len(s.LiveVideos) != len(other.LiveVideos) {
+		return false
+	}
+
+	for _, a := range s.LiveVideos {
+		found := false
+		for _, b := range other.LiveVideos {
+			if a == b {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Reports whether both states list the same live and upcoming streams, with
+// the same details (e.g., scheduled start times and concurrent viewers).
+func (s LiveState) sameStreams(other LiveState) bool {
+	if len(s.Streams) != len(other.Streams) {
+		return false
+	}
+
+	for _, a := range s.Streams {
+		found := false
+		for _, b := range other.Streams {
+			if a.ID == b.ID {
+				found = a.equal(b)
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+//////////////////////////////////////////////////
+
+type EventKind uint
+
+const (
+	EventTracked EventKind = (iota + 1)
+	EventUntracked
+	EventLiveStarted
+	EventLiveEnded
+	EventLiveVideosChanged
+	// The channel has been found to be terminated or deleted.
+	EventGone
+	// A channel previously found to be gone is reachable again.
+	EventRestored
+	// The live and upcoming streams (or their details, e.g. a title or a
+	// scheduled start time) have changed, but not the set of live videos;
+	// changes in the number of concurrent viewers alone do not count.
+	EventStreamsChanged
+)
+
+var InvalidEventKind = errors.New("invalid event kind")
+
+func (k EventKind) String() string {
+	switch k {
+	case EventTracked:
+		return "tracked"
+	case EventUntracked:
+		return "untracked"
+	case EventLiveStarted:
+		return "live_started"
+	case EventLiveEnded:
+		return "live_ended"
+	case EventLiveVideosChanged:
+		return "live_videos_changed"
+	case EventGone:
+		return "gone"
+	case EventRestored:
+		return "restored"
+	case EventStreamsChanged:
+		return "streams_changed"
+	}
+
+	return ""
+}
+
+func (k EventKind) MarshalText() ([]byte, error) {
+	s := k.String()
+	if s == "" {
+		return nil, InvalidEventKind
+	}
+
+	return []byte(s), nil
+}
+
+func (k *EventKind) UnmarshalText(b []byte) error {
+	s := string(b)
+	for kk := EventTracked; kk <= EventStreamsChanged; kk++ {
+		if kk.String() == s {
+			*k = kk
+			return nil
+		}
+	}
+
+	return InvalidEventKind
+}
+
+// Describes a change in the live state of a single tracked handle.
+type Event struct {
+	Kind     EventKind `json:"kind"`
+	State    LiveState `json:"state"`
+	Previous LiveState `json:"previous"`
+}
+
+//////////////////////////////////////////////////
+
+// Keeps the most recently observed live state of every handle, and turns
+// crawler results (as received from Listen) into a sequence of events.
+//
+// Update is expected to be called from a single goroutine; the remaining
+// methods are safe for concurrent use.
+type StateTracker struct {
+	states csync.Map[Handle, LiveState]
+}
+
+func (t *StateTracker) State(handle Handle) (state LiveState, ok bool) {
+	return t.states.Load(handle)
+}
+
+func (t *StateTracker) Snapshot() (states []LiveState) {
+	states = []LiveState{}
+
+	t.states.Range(func(_ Handle, state LiveState) bool {
+		states = append(states, state)
+
+		return true
+	})
+
+	return
+}
+
+func (t *StateTracker) Reset() {
+	t.states.Range(func(handle Handle, _ LiveState) bool {
+		t.states.Delete(handle)
+
+		return true
+	})
+}
+
+func (t *StateTracker) Update(result *crawly.Result) (events []Event) {
+	if result == nil || !result.Valid {
+		return
+	}
+
+	timestamp := result.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	for _, tr := range result.Entities {
+		handle, ok := tr.Entity.Value.Handle.(Handle)
+		if !ok || !handle.Valid() {
+			continue
+		}
+
+		prev, tracked := t.states.Load(handle)
+		data, hasData := tr.Entity.Value.Data.(EntityData)
+
+		if tr.Entity.Action == crawly.TrackingActionRemove {
+			state := prev
+			if !tracked {
+				state = liveStateFromEntityData(handle, data, timestamp)
+			}
+			state.Timestamp = timestamp
+
+			// NOTE: an entity may get untracked right as it is found to be gone
+			// (see GonePolicyUntrack).
+			if hasData && data.Gone && !prev.Gone {
+				state.Live = false
+				state.LiveVideos = []string{}
+				state.Gone = true
+				state.GoneReason = data.GoneReason
+
+				events = append(events, Event{
+					Kind:     EventGone,
+					State:    state,
+					Previous: prev,
+				})
+			}
+
+			if tracked {
+				t.states.Delete(handle)
+
+				events = append(events, Event{
+					Kind:     EventUntracked,
+					State:    state,
+					Previous: prev,
+				})
+			}
+
+			continue
+		}
+
+		if !hasData {
+			continue
+		}
+
+		state := liveStateFromEntityData(handle, data, timestamp)
+		liveChanged := state.Live != prev.Live || !state.sameLiveVideos(prev)
+		streamsChanged := !state.sameStreams(prev)
+		if tracked && !liveChanged && !streamsChanged && state.Gone == prev.Gone {
+			continue
+		}
+		t.states.Store(handle, state)
+
+		if !tracked {
+			events = append(events, Event{
+				Kind:  EventTracked,
+				State: state,
+			})
+
+			if state.Live {
+				events = append(events, Event{
+					Kind:  EventLiveStarted,
+					State: state,
+				})
+			}
+			if state.Gone {
+				events = append(events, Event{
+					Kind:  EventGone,
+					State: state,
+				})
+			}
+
+			continue
+		}
+
+		if liveChanged {
+			var kind EventKind
+			switch {
+			case state.Live && !prev.Live:
+				kind = EventLiveStarted
+			case !state.Live && prev.Live:
+				kind = EventLiveEnded
+			default:
+				kind = EventLiveVideosChanged
+			}
+
+			events = append(events, Event{
+				Kind:     kind,
+				State:    state,
+				Previous: prev,
+			})
+		} else if streamsChanged {
+			events = append(events, Event{
+				Kind:     EventStreamsChanged,
+				State:    state,
+				Previous: prev,
+			})
+		}
+
+		if state.Gone && !prev.Gone {
+			events = append(events, Event{
+				Kind:     EventGone,
+				State:    state,
+				Previous: prev,
+			})
+		} else if !state.Gone && prev.Gone {
+			events = append(events, Event{
+				Kind:     EventRestored,
+				State:    state,
+				Previous: prev,
+			})
+		}
+	}
+
+	return
+}
@@ -0,0 +1,87 @@
+package youtube
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rubpy/crawly"
+)
+
+func trackerResult(handle Handle, data EntityData, timestamp time.Time) *crawly.Result {
+	var tr crawly.TrackingResult
+	tr.Entity.Value = crawly.Entity{Handle: handle, Data: data}
+
+	return &crawly.Result{
+		Valid:     true,
+		Timestamp: timestamp,
+		Entities:  map[crawly.Handle]crawly.TrackingResult{handle: tr},
+	}
+}
+
+func eventKinds(events []Event) (kinds []EventKind) {
+	for _, event := range events {
+		kinds = append(kinds, event.Kind)
+	}
+
+	return
+}
+
+func TestStateTrackerUpdate(t *testing.T) {
+	handle := ChannelID("UCSJ4gkVC6NrvII8umztf0Ow")
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	upcoming := LiveVideo{ID: "aaaaaaaaaaa", Kind: LiveVideoUpcoming, ScheduledStartTime: now.Add(time.Hour)}
+	live := LiveVideo{ID: "aaaaaaaaaaa", Kind: LiveVideoLive, ActualStartTime: now, ConcurrentViewers: 10}
+	busier := live
+	busier.ConcurrentViewers = 20
+
+	steps := []struct {
+		name string
+		data EntityData
+		want []EventKind
+	}{
+		{"tracked", EntityData{}, []EventKind{EventTracked}},
+		{"unchanged", EntityData{}, nil},
+		{"scheduled", EntityData{Streams: []LiveVideo{upcoming}}, []EventKind{EventStreamsChanged}},
+		{"started", EntityData{Live: true, LiveVideos: []string{live.ID}, Streams: []LiveVideo{live}}, []EventKind{EventLiveStarted}},
+		{"viewers", EntityData{Live: true, LiveVideos: []string{live.ID}, Streams: []LiveVideo{busier}}, nil},
+		{"gone", EntityData{Gone: true, GoneReason: GoneTerminated}, []EventKind{EventLiveEnded, EventGone}},
+		{"restored", EntityData{}, []EventKind{EventRestored}},
+	}
+
+	var tracker StateTracker
+	for i, step := range steps {
+		events := tracker.Update(trackerResult(handle, step.data, now.Add(time.Duration(i)*time.Minute)))
+
+		got := eventKinds(events)
+		if len(got) != len(step.want) {
+			t.Fatalf("%s: got events %v, want %v", step.name, got, step.want)
+		}
+		for j := range got {
+			if got[j] != step.want[j] {
+				t.Fatalf("%s: got events %v, want %v", step.name, got, step.want)
+			}
+		}
+	}
+}
+
+func TestEventJSON(t *testing.T) {
+	event := Event{
+		Kind:  EventTracked,
+		State: LiveState{Handle: ChannelID("UCSJ4gkVC6NrvII8umztf0Ow"), LiveVideos: []string{}},
+	}
+
+	b, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	var decoded Event
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if decoded.Kind != event.Kind || decoded.State.Handle != event.State.Handle || decoded.Previous.Handle.Valid() {
+		t.Fatalf("got %+v, want %+v", decoded, event)
+	}
+}
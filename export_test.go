@@ -0,0 +1,3 @@
+package youtube
+
+var EntityHandler = (*Crawler).entityHandler
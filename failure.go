@@ -0,0 +1,131 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+//////////////////////////////////////////////////
+
+// Consecutive failures of an entity (or a video candidate), along with when
+// it may be retried; once MaximumFailures is reached, it is parked (i.e., no
+// longer retried) until reset via ResetFailures. Retries counts consecutive
+// errors which do not count towards parking it (see countsAsFailure).
+type FailureState struct {
+	Failures    int       `json:"failures"`
+	Retries     int       `json:"retries,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastFailure time.Time `json:"last_failure"`
+	RetryAfter  time.Time `json:"retry_after"`
+	Parked      bool      `json:"parked"`
+}
+
+// Whether an attempt may be made at the given time.
+func (f *FailureState) Ready(now time.Time) bool {
+	return !f.Parked && !now.Before(f.RetryAfter)
+}
+
+// Records a failed attempt, and schedules the next one (with exponential
+// backoff); returns true if the failure got it parked. Errors which are not
+// about the entity (or video) itself (see countsAsFailure) back it off all
+// the same, but do not count towards parking it.
+func (f *FailureState) fail(err error, now time.Time, settings CrawlerSettings) (parked bool) {
+	f.LastFailure = now
+	if err != nil {
+		f.LastError = err.Error()
+	}
+
+	counts := countsAsFailure(err)
+	if counts {
+		f.Failures++
+	} else {
+		f.Retries++
+	}
+
+	f.RetryAfter = now.Add(settings.failureBackoff(f.Failures + f.Retries))
+	if !counts {
+		return false
+	}
+
+	if settings.MaximumFailures > 0 && f.Failures >= settings.MaximumFailures && !f.Parked {
+		f.Parked = true
+		return true
+	}
+
+	return false
+}
+
+// Records a successful attempt.
+func (f *FailureState) succeed() {
+	*f = FailureState{}
+}
+
+// Whether an error is (likely) about the entity or video being processed,
+// rather than about the crawler as a whole: open circuit breakers, exhausted
+// quota, cancellation and temporary fetch errors (server errors, timeouts,
+// blocks) are not.
+func countsAsFailure(err error) bool {
+	if errors.Is(err, CircuitOpen) || errors.Is(err, QuotaExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var fe *FetchError
+	if errors.As(err, &fe) && fe.Temporary() {
+		return false
+	}
+
+	return true
+}
+
+// Returns the delay before the next attempt following the given number of
+// consecutive failures: FailureBackoffBase, doubled with every further
+// failure (up to FailureBackoffMaximum), minus a random FailureBackoffJitter
+// fraction of it.
+func (settings CrawlerSettings) failureBackoff(failures int) time.Duration {
+	if failures < 1 || settings.FailureBackoffBase <= 0 {
+		return 0
+	}
+
+	d := settings.FailureBackoffBase
+	for i := 1; i < failures && d < settings.FailureBackoffMaximum; i++ {
+		d *= 2
+	}
+	d = min(d, settings.FailureBackoffMaximum)
+
+	if settings.FailureBackoffJitter > 0 {
+		d -= time.Duration(rand.Float64() * settings.FailureBackoffJitter * float64(d))
+	}
+
+	return d
+}
+
+//////////////////////////////////////////////////
+
+// Clears the failure state of a tracked handle and of all of its video
+// candidates (un-parking them); takes effect on the handle's next pass.
+// Returns false (doing nothing) if the handle is not tracked.
+func (cr *Crawler) ResetFailures(handle Handle) (tracked bool) {
+	handle = cr.canonicalHandle(handle)
+	if !cr.Crawler.IsTracked(handle) {
+		return false
+	}
+
+	cr.failureResets.Store(handle, struct{}{})
+	return true
+}
+
+// Applies a pending ResetFailures (if any) to the entity's data.
+func (cr *Crawler) applyFailureReset(handle Handle, data *EntityData) bool {
+	if _, ok := cr.failureResets.LoadAndDelete(handle); !ok {
+		return false
+	}
+
+	data.FailureState.succeed()
+	for idx := range data.FeedVideoCandidates {
+		data.FeedVideoCandidates[idx].FailureState.succeed()
+	}
+
+	return true
+}
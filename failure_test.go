@@ -0,0 +1,62 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFailureStateFail(t *testing.T) {
+	settings := DefaultSettings
+	settings.FailureBackoffJitter = 0
+	settings.MaximumFailures = 2
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		err    error
+		counts bool
+	}{
+		{"not found", newFetchError(EndpointChannelFeed, "", http.StatusNotFound, nil, UnexpectedStatus), true},
+		{"parse", errors.New("invalid feed"), true},
+		{"server error", newFetchError(EndpointChannelFeed, "", http.StatusBadGateway, nil, UnexpectedStatus), false},
+		{"unavailable", newFetchError(EndpointChannelFeed, "", http.StatusServiceUnavailable, nil, UnexpectedStatus), false},
+		{"blocked", newFetchError(EndpointChannelFeed, "", http.StatusTooManyRequests, nil, &BlockedError{Reason: BlockRateLimited}), false},
+		{"circuit open", fmt.Errorf("FetchChannelXMLFeed: %w", newFetchError(EndpointChannelFeed, "", 0, nil, CircuitOpen)), false},
+		{"quota", fmt.Errorf("FetchUploads: %w", QuotaExceeded), false},
+	}
+
+	for _, tt := range tests {
+		var f FailureState
+		for i := 0; i < settings.MaximumFailures; i++ {
+			f.fail(tt.err, now, settings)
+		}
+
+		if f.LastError != tt.err.Error() {
+			t.Errorf("%s: got LastError %q", tt.name, f.LastError)
+		}
+		if counted := f.Failures > 0; counted != tt.counts {
+			t.Errorf("%s: got %d failures, want them counted: %t", tt.name, f.Failures, tt.counts)
+		}
+		if f.Parked != tt.counts {
+			t.Errorf("%s: got parked %t", tt.name, f.Parked)
+		}
+		if f.Ready(now) {
+			t.Errorf("%s: ready right after failing", tt.name)
+		}
+
+		// Errors not counted towards parking are still backed off.
+		if !tt.counts {
+			want := now.Add(settings.failureBackoff(settings.MaximumFailures))
+			if !f.RetryAfter.Equal(want) {
+				t.Errorf("%s: got RetryAfter %s, want %s", tt.name, f.RetryAfter, want)
+			}
+			if !f.Ready(want) {
+				t.Errorf("%s: not ready after backing off", tt.name)
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+//////////////////////////////////////////////////
+
+var UnexpectedStatus = errors.New("unexpected HTTP status")
+
+// Describes a failed request to one of the (scraping) endpoints; every error
+// returned by the fetch functions past argument validation is a *FetchError
+// (use errors.As), wrapping the actual cause (e.g., a transport error, a
+// BlockedError, a CircuitOpenError, UnexpectedStatus or a parsing error).
+type FetchError struct {
+	Endpoint Endpoint
+	URL      string
+
+	// Zero if no response has been received.
+	StatusCode int
+	// Beginning of the response body (if it has been read).
+	BodySnippet string
+
+	Err error
+}
+
+// Maximum length (in bytes) of FetchError.BodySnippet.
+var maximumBodySnippetLength = 256
+
+func (e *FetchError) Error() string {
+	var s strings.Builder
+
+	s.WriteString(e.Endpoint.String())
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&s, " (HTTP %d)", e.StatusCode)
+	}
+	s.WriteString(": ")
+	if e.Err != nil {
+		s.WriteString(e.Err.Error())
+	} else {
+		s.WriteString("fetch failed")
+	}
+
+	return s.String()
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// Whether the requested resource does not exist (HTTP 404 or 410).
+func (e *FetchError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone
+}
+
+// Whether the request may succeed if retried later (no response at all, a
+// server error, a timeout, or a block).
+func (e *FetchError) Temporary() bool {
+	if errors.Is(e.Err, Blocked) || errors.Is(e.Err, CircuitOpen) {
+		return true
+	}
+
+	switch {
+	case e.StatusCode == 0:
+		return true
+	case e.StatusCode == http.StatusRequestTimeout, e.StatusCode == http.StatusTooManyRequests:
+		return true
+	case e.StatusCode >= 500:
+		return true
+	}
+
+	return false
+}
+
+func newFetchError(endpoint Endpoint, rawURL string, statusCode int, body []byte, err error) *FetchError {
+	return &FetchError{
+		Endpoint: endpoint,
+		URL:      rawURL,
+
+		StatusCode:  statusCode,
+		BodySnippet: bodySnippet(body),
+
+		Err: err,
+	}
+}
+
+func bodySnippet(body []byte) string {
+	if len(body) > maximumBodySnippetLength {
+		// NOTE: avoid cutting a multi-byte character in half.
+		cut := maximumBodySnippetLength
+		for cut > 0 && !utf8.RuneStart(body[cut]) {
+			cut--
+		}
+
+		body = body[:cut]
+	}
+
+	return strings.ToValidUTF8(string(body), "�")
+}
+
+//////////////////////////////////////////////////
+
+// Maximum number of bytes of a streamed response body kept around for error
+// reporting (e.g., block detection and FetchError.BodySnippet).
+var maximumCapturedBodyLength = 64 << 10
+
+// Keeps (a copy of) the beginning of a streamed response body.
+type capturedBody struct {
+	b []byte
+}
+
+func newCapturedBody() *capturedBody {
+	return &capturedBody{}
+}
+
+func (c *capturedBody) Write(p []byte) (int, error) {
+	if room := maximumCapturedBodyLength - len(c.b); room > 0 {
+		c.b = append(c.b, p[:min(room, len(p))]...)
+	}
+
+	return len(p), nil
+}
+
+func (c *capturedBody) Bytes() []byte {
+	return c.b
+}
+
+// Reads a whole (error) response body, but no more than limit bytes of it
+// (a limit of 0 or less means no limit); anything past the limit is
+// discarded.
+func readBody(r io.Reader, limit int64) ([]byte, error) {
+	if limit > 0 {
+		r = io.LimitReader(r, limit)
+	}
+
+	return io.ReadAll(r)
+}
@@ -0,0 +1,131 @@
+package youtube
+
+import (
+	"fmt"
+	"regexp"
+)
+
+//////////////////////////////////////////////////
+
+// Rules deciding which live (and upcoming) videos of a handle count towards
+// EntityData.Live; videos which do not pass are still tracked, but reported
+// in EntityData.FilteredVideos instead of LiveVideos (and Streams).
+//
+// Patterns are regular expressions (RE2 syntax; e.g., "(?i)tournament" to
+// ignore case), and empty ones are ignored. The Exclude* patterns apply to
+// what the Include* patterns let through.
+type StreamFilter struct {
+	IncludeTitle       string `json:"include_title,omitempty"`
+	ExcludeTitle       string `json:"exclude_title,omitempty"`
+	IncludeDescription string `json:"include_description,omitempty"`
+	ExcludeDescription string `json:"exclude_description,omitempty"`
+
+	// Live videos with fewer concurrent viewers do not count (upcoming ones
+	// are not affected).
+	MinimumViewers uint64 `json:"minimum_viewers,omitempty"`
+
+	// Kinds of videos that count (all of them, if empty).
+	Kinds []LiveVideoKind `json:"kinds,omitempty"`
+}
+
+func (f *StreamFilter) validate() (errs SettingsErrors) {
+	if f == nil {
+		return
+	}
+
+	pattern := func(field string, v string) {
+		if _, err := regexp.Compile(v); err != nil {
+			errs = append(errs, &SettingsError{"Filter." + field, v, fmt.Sprintf("must be a valid regular expression (%v)", err)})
+		}
+	}
+
+	pattern("IncludeTitle", f.IncludeTitle)
+	pattern("ExcludeTitle", f.ExcludeTitle)
+	pattern("IncludeDescription", f.IncludeDescription)
+	pattern("ExcludeDescription", f.ExcludeDescription)
+
+	for _, kind := range f.Kinds {
+		if kind == LiveVideoNone {
+			errs = append(errs, &SettingsError{"Filter.Kinds", f.Kinds, "must only contain live, upcoming or premiere"})
+			break
+		}
+	}
+
+	return
+}
+
+// Returns the compiled form of a (validated) filter pattern, caching it.
+func (cr *Crawler) filterPattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := cr.filterPatterns.Load(pattern); ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	cr.filterPatterns.Store(pattern, re)
+
+	return re, nil
+}
+
+// Reports whether a live (or upcoming) video of the given kind passes f (a
+// nil filter lets everything through).
+func (cr *Crawler) passesFilter(f *StreamFilter, vc *VideoCandidate, kind LiveVideoKind) (ok bool, err error) {
+	if f == nil {
+		return true, nil
+	}
+
+	match := func(pattern string, s string, empty bool) (bool, error) {
+		if pattern == "" {
+			return empty, nil
+		}
+
+		re, err := cr.filterPattern(pattern)
+		if err != nil {
+			return false, err
+		}
+
+		return re.MatchString(s), nil
+	}
+
+	rules := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{f.IncludeTitle, vc.Title, true},
+		{f.ExcludeTitle, vc.Title, false},
+		{f.IncludeDescription, vc.Description, true},
+		{f.ExcludeDescription, vc.Description, false},
+	}
+	for _, rule := range rules {
+		matched, err := match(rule.pattern, rule.s, rule.want)
+		if err != nil {
+			return false, err
+		}
+		if matched != rule.want {
+			return false, nil
+		}
+	}
+
+	if vc.Live && vc.ConcurrentViewers < f.MinimumViewers {
+		return false, nil
+	}
+
+	if len(f.Kinds) > 0 {
+		found := false
+		for _, k := range f.Kinds {
+			if k == kind {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -3,6 +3,9 @@ module github.com/rubpy/crawly-live-youtube
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/bogdanfinn/fhttp v0.5.24
+	github.com/bogdanfinn/tls-client v1.6.1
 	github.com/eiannone/keyboard v0.0.0-20220611211555-0d226195f203
 	github.com/lmittmann/tint v1.0.2
 	github.com/rubpy/crawly v0.0.0-20231019123451-cf7a88a6687d
@@ -11,14 +14,13 @@ require (
 	github.com/rubpy/crawly/csync v0.0.0-20231019123451-cf7a88a6687d
 	golang.org/x/net v0.17.0
 	google.golang.org/api v0.147.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	cloud.google.com/go/compute v1.23.0 // indirect
 	cloud.google.com/go/compute/metadata v0.2.3 // indirect
 	github.com/andybalholm/brotli v1.0.4 // indirect
-	github.com/bogdanfinn/fhttp v0.5.24 // indirect
-	github.com/bogdanfinn/tls-client v1.6.1 // indirect
 	github.com/bogdanfinn/utls v1.5.16 // indirect
 	github.com/golang/groupcache v0.0.0-20210331224755-41bb18bfe9da // indirect
 	github.com/golang/protobuf v1.5.3 // indirect
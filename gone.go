@@ -0,0 +1,288 @@
+package youtube
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/rubpy/crawly/clog"
+)
+
+//////////////////////////////////////////////////
+
+// Reason for which a channel no longer exists.
+type GoneReason uint
+
+const (
+	GoneNone GoneReason = iota
+	// The channel page reports the account as terminated (or removed).
+	GoneTerminated
+	// The channel is unknown to the Data API (e.g., deleted by its owner).
+	GoneDeleted
+)
+
+var goneReasonNames = map[GoneReason]string{
+	GoneNone:       "",
+	GoneTerminated: "terminated",
+	GoneDeleted:    "deleted",
+}
+
+var InvalidGoneReason = errors.New("invalid gone reason")
+
+func (r GoneReason) String() string {
+	return goneReasonNames[r]
+}
+
+func (r GoneReason) MarshalText() ([]byte, error) {
+	name, ok := goneReasonNames[r]
+	if !ok {
+		return nil, InvalidGoneReason
+	}
+
+	return []byte(name), nil
+}
+
+func (r *GoneReason) UnmarshalText(b []byte) error {
+	for reason, name := range goneReasonNames {
+		if string(b) == name {
+			*r = reason
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %q", InvalidGoneReason, b)
+}
+
+//////////////////////////////////////////////////
+
+// What the crawler does with a tracked channel once it is found to be gone.
+type GonePolicy uint
+
+const (
+	// Keep polling the channel as usual.
+	GonePolicyKeep GonePolicy = iota
+	// Stop tracking the channel.
+	GonePolicyUntrack
+	// Keep tracking the channel, but fetch its feed no more often than
+	// GoneFetchChannelFeedDelay (in case it gets restored).
+	GonePolicyDowngrade
+)
+
+var gonePolicyNames = map[GonePolicy]string{
+	GonePolicyKeep:      "keep",
+	GonePolicyUntrack:   "untrack",
+	GonePolicyDowngrade: "downgrade",
+}
+
+var InvalidGonePolicy = errors.New("invalid gone policy")
+
+func (p GonePolicy) String() string {
+	if name, ok := gonePolicyNames[p]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+func (p GonePolicy) MarshalText() ([]byte, error) {
+	name, ok := gonePolicyNames[p]
+	if !ok {
+		return nil, InvalidGonePolicy
+	}
+
+	return []byte(name), nil
+}
+
+func (p *GonePolicy) UnmarshalText(b []byte) error {
+	for policy, name := range gonePolicyNames {
+		if string(b) == name {
+			*p = policy
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %q", InvalidGonePolicy, b)
+}
+
+//////////////////////////////////////////////////
+
+var ChannelTerminated = errors.New("channel has been terminated")
+
+var terminatedBodyMarkers = [][]byte{
+	[]byte("This account has been terminated"),
+	[]byte("This channel was removed"),
+}
+
+func isTerminatedPage(body []byte) bool {
+	for _, marker := range terminatedBodyMarkers {
+		if bytes.Contains(body, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Checks whether a channel still exists (typically after its feed has
+// responded with 404): first by looking for a termination notice on its
+// channel page, then (unless the page looks fine) by asking the Data API.
+func (cr *Crawler) CheckChannelGone(ctx context.Context, channelID string) (reason GoneReason, err error) {
+	if channelID == "" || !IsValidChannelID(channelID) {
+		err = InvalidChannelID
+		return
+	}
+
+	if cr.client == nil {
+		err = NilClient
+		return
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	} else {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+	}
+
+	pageURL := &url.URL{
+		Scheme: "https",
+		Host:   "www.youtube.com",
+		Path:   "channel/" + channelID,
+	}
+
+	profile := cr.loadRequestProfile()
+	profile.applyQuery(EndpointChannelIndex, pageURL)
+
+	rawPageURL := pageURL.String()
+
+	resp, err := cr.request(ctx, EndpointChannelIndex, rawPageURL, profile.header(EndpointChannelIndex, cr.now()))
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := readBody(resp.Body, cr.loadEffectiveSettings().MaximumResponseBodySize)
+	if err != nil {
+		return GoneNone, newFetchError(EndpointChannelIndex, rawPageURL, resp.StatusCode, body, err)
+	}
+
+	if isTerminatedPage(body) {
+		return GoneTerminated, nil
+	}
+	if resp.StatusCode == 200 {
+		cr.recordSuccess(ctx, EndpointChannelIndex)
+		return GoneNone, nil
+	}
+
+	exists, err := cr.CheckChannelExists(ctx, channelID)
+	if err != nil {
+		return
+	}
+	if !exists {
+		return GoneDeleted, nil
+	}
+
+	return GoneNone, nil
+}
+
+// Asks the YouTube Data API whether a channel exists.
+func (cr *Crawler) CheckChannelExists(ctx context.Context, channelID string) (exists bool, err error) {
+	if channelID == "" || !IsValidChannelID(channelID) {
+		err = InvalidChannelID
+		return
+	}
+
+	if cr.service == nil {
+		err = NilService
+		return
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	} else {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+	}
+
+	call := cr.service.Channels.List([]string{"id"})
+	call.Context(ctx)
+	call.Id(channelID)
+
+	cr.recordQuota("channels.list", quotaCostList)
+	resp, err := call.Do()
+	if err != nil {
+		err = fmt.Errorf("youtube.ChannelsService.List: %w", err)
+		return
+	}
+
+	for _, item := range resp.Items {
+		if item.Id == channelID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+//////////////////////////////////////////////////
+
+// Marks the entity as gone if the channel no longer exists (checking only if
+// it is not already known to be gone).
+func (cr *Crawler) detectGone(ctx context.Context, channelID string, data *EntityData) (gone bool, err error) {
+	if !data.Gone {
+		reason, err := cr.CheckChannelGone(ctx, channelID)
+		if err != nil {
+			return false, fmt.Errorf("CheckChannelGone: %w", err)
+		}
+		if reason == GoneNone {
+			return false, nil
+		}
+
+		data.Gone = true
+		data.GoneReason = reason
+		data.GoneSince = cr.now()
+
+		cr.Log(ctx, clog.Params{
+			Message: "channelGone",
+			Level:   slog.LevelWarn,
+
+			Values: clog.ParamGroup{
+				"channelID": channelID,
+				"reason":    reason.String(),
+			},
+		})
+	}
+
+	data.Live = false
+	data.LiveVideos = []string{}
+	data.LiveVideoChannels = nil
+	data.Feed = nil
+	data.FeedVideoCandidates = nil
+
+	// NOTE: makes the (possibly downgraded) feed fetch delay count from now.
+	data.LastFeedFetch = cr.now()
+
+	return true, nil
+}
+
+func (cr *Crawler) markRestored(ctx context.Context, channelID string, data *EntityData) {
+	cr.Log(ctx, clog.Params{
+		Message: "channelRestored",
+		Level:   slog.LevelInfo,
+
+		Values: clog.ParamGroup{
+			"channelID": channelID,
+			"reason":    data.GoneReason.String(),
+		},
+	})
+
+	data.Gone = false
+	data.GoneReason = GoneNone
+	data.GoneSince = time.Time{}
+}
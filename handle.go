@@ -1,6 +1,8 @@
 package youtube
 
 import (
+	"errors"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -10,8 +12,8 @@ import (
 //////////////////////////////////////////////////
 
 type Handle struct {
-	Type  HandleType
-	Value string
+	Type  HandleType `json:"type"`
+	Value string     `json:"value"`
 }
 
 func (h Handle) Valid() bool {
@@ -42,6 +44,7 @@ type HandleType uint
 const (
 	HandleChannelID HandleType = (iota + 1)
 	HandleChannelURL
+	HandlePlaylistID
 )
 
 func (ht HandleType) String() string {
@@ -50,11 +53,47 @@ func (ht HandleType) String() string {
 		return "ChannelID"
 	case HandleChannelURL:
 		return "ChannelURL"
+	case HandlePlaylistID:
+		return "PlaylistID"
 	}
 
 	return ""
 }
 
+// NOTE: the zero HandleType (e.g., of an Event's Previous state, for a handle
+// that had none) is written as an empty string.
+func (ht HandleType) MarshalText() ([]byte, error) {
+	if ht == 0 {
+		return []byte{}, nil
+	}
+
+	s := ht.String()
+	if s == "" {
+		return nil, InvalidHandleType
+	}
+
+	return []byte(s), nil
+}
+
+func (ht *HandleType) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "":
+		*ht = 0
+	case "ChannelID":
+		*ht = HandleChannelID
+	case "ChannelURL":
+		*ht = HandleChannelURL
+	case "PlaylistID":
+		*ht = HandlePlaylistID
+	default:
+		return InvalidHandleType
+	}
+
+	return nil
+}
+
+var InvalidHandleType = errors.New("invalid handle type")
+
 //////////////////////////////////////////////////
 
 func ChannelID(channelID string) Handle {
@@ -64,3 +103,45 @@ func ChannelID(channelID string) Handle {
 func ChannelURL(channelURL string) Handle {
 	return Handle{HandleChannelURL, channelURL}
 }
+
+func PlaylistID(playlistID string) Handle {
+	return Handle{HandlePlaylistID, playlistID}
+}
+
+// Parses a handle from its textual form: a channel URL, a channel handle
+// (e.g., "@LofiGirl"), a channel ID, a playlist ID, or a playlist URL (e.g.,
+// "https://www.youtube.com/playlist?list=PL...").
+func ParseHandle(s string) (handle Handle, err error) {
+	s = strings.TrimSpace(s)
+
+	switch {
+	case IsValidPlaylistID(s):
+		handle = PlaylistID(s)
+	case playlistIDFromURL(s) != "":
+		handle = PlaylistID(playlistIDFromURL(s))
+	case strings.HasPrefix(s, "@") && len(s) > 1:
+		handle = ChannelURL("https://www.youtube.com/" + s)
+	case IsValidChannelURL(s):
+		handle = ChannelURL(s)
+	case IsValidChannelID(s):
+		handle = ChannelID(s)
+	default:
+		err = crawly.InvalidHandle
+	}
+
+	return
+}
+
+// Returns the playlist ID from a playlist URL, or an empty string.
+func playlistIDFromURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Path != "/playlist" {
+		return ""
+	}
+
+	if playlistID := u.Query().Get("list"); IsValidPlaylistID(playlistID) {
+		return playlistID
+	}
+
+	return ""
+}
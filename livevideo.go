@@ -0,0 +1,134 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+//////////////////////////////////////////////////
+
+type LiveVideoKind uint
+
+const (
+	LiveVideoNone LiveVideoKind = iota
+	// A livestream which is live now.
+	LiveVideoLive
+	// A livestream which has been scheduled, but not yet started.
+	LiveVideoUpcoming
+	// A premiere (i.e., a pre-recorded video played back "live"), whether
+	// upcoming or live now.
+	LiveVideoPremiere
+)
+
+var liveVideoKindNames = map[LiveVideoKind]string{
+	LiveVideoNone:     "",
+	LiveVideoLive:     "live",
+	LiveVideoUpcoming: "upcoming",
+	LiveVideoPremiere: "premiere",
+}
+
+var InvalidLiveVideoKind = errors.New("invalid live video kind")
+
+func (k LiveVideoKind) String() string {
+	return liveVideoKindNames[k]
+}
+
+func (k LiveVideoKind) MarshalText() ([]byte, error) {
+	name, ok := liveVideoKindNames[k]
+	if !ok {
+		return nil, InvalidLiveVideoKind
+	}
+
+	return []byte(name), nil
+}
+
+func (k *LiveVideoKind) UnmarshalText(b []byte) error {
+	for kind, name := range liveVideoKindNames {
+		if string(b) == name {
+			*k = kind
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %q", InvalidLiveVideoKind, b)
+}
+
+//////////////////////////////////////////////////
+
+// A live (or upcoming) video, with the details gathered while checking it
+// (from the feed entry, the channel's "Live" tab and the Data API).
+type LiveVideo struct {
+	ID           string        `json:"id"`
+	ChannelID    string        `json:"channel_id"`
+	Kind         LiveVideoKind `json:"kind"`
+	Title        string        `json:"title"`
+	URL          string        `json:"url"`
+	ThumbnailURL string        `json:"thumbnail_url"`
+
+	ScheduledStartTime time.Time `json:"scheduled_start_time"`
+	ActualStartTime    time.Time `json:"actual_start_time"`
+	ConcurrentViewers  uint64    `json:"concurrent_viewers"`
+}
+
+// Compares every field but ConcurrentViewers (which changes with nearly every
+// check of a live video, and would otherwise make every pass a change).
+func (v LiveVideo) equal(other LiveVideo) bool {
+	return v.ID == other.ID &&
+		v.ChannelID == other.ChannelID &&
+		v.Kind == other.Kind &&
+		v.Title == other.Title &&
+		v.URL == other.URL &&
+		v.ThumbnailURL == other.ThumbnailURL &&
+		v.ScheduledStartTime.Equal(other.ScheduledStartTime) &&
+		v.ActualStartTime.Equal(other.ActualStartTime)
+}
+
+func VideoURL(videoID string) string {
+	return "https://www.youtube.com/watch?v=" + url.QueryEscape(videoID)
+}
+
+// Returns vc as a LiveVideo of the given kind.
+func liveVideoFromCandidate(vc *VideoCandidate, kind LiveVideoKind) LiveVideo {
+	thumbnailURL := vc.ThumbnailURL
+	if thumbnailURL == "" {
+		thumbnailURL = "https://i.ytimg.com/vi/" + url.PathEscape(vc.ID) + "/hqdefault.jpg"
+	}
+
+	return LiveVideo{
+		ID:           vc.ID,
+		ChannelID:    vc.ChannelID,
+		Kind:         kind,
+		Title:        vc.Title,
+		URL:          VideoURL(vc.ID),
+		ThumbnailURL: thumbnailURL,
+
+		ScheduledStartTime: vc.ScheduledStartTime,
+		ActualStartTime:    vc.ActualStartTime,
+		ConcurrentViewers:  vc.ConcurrentViewers,
+	}
+}
+
+// Returns the kind of LiveVideo that vc is (LiveVideoNone, unless it is live
+// or upcoming).
+func (vc *VideoCandidate) kind() LiveVideoKind {
+	switch {
+	case !vc.LiveGenuine:
+		return LiveVideoNone
+	case vc.Premiere && (vc.Live || vc.upcoming()):
+		return LiveVideoPremiere
+	case vc.Live:
+		return LiveVideoLive
+	case vc.upcoming():
+		return LiveVideoUpcoming
+	}
+
+	return LiveVideoNone
+}
+
+// Reports whether vc has been checked to be a livestream which is scheduled,
+// but has neither started nor finished.
+func (vc *VideoCandidate) upcoming() bool {
+	return vc.LiveGenuine && !vc.Live && !vc.NotLivestream && !vc.LivestreamFinished && !vc.ScheduledStartTime.IsZero()
+}
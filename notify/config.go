@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	youtube "github.com/rubpy/crawly-live-youtube"
+)
+
+//////////////////////////////////////////////////
+
+type config struct {
+	logger *slog.Logger
+	client *http.Client
+
+	rules     *Rules
+	dryRun    bool
+	queueSize int
+}
+
+var (
+	NilConfig        = errors.New("config is nil")
+	InvalidQueueSize = errors.New("queue size must not be negative")
+)
+
+// Maximum number of notifications queued per sink, by default (see
+// WithQueueSize).
+var DefaultQueueSize = 100
+
+func validateConfig(cfg *config) error {
+	if cfg == nil {
+		return NilConfig
+	}
+
+	if cfg.queueSize < 0 {
+		return InvalidQueueSize
+	}
+
+	if cfg.rules != nil {
+		if err := cfg.rules.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func buildEngineFromConfig(cr *youtube.Crawler, cfg *config) (e *Engine, err error) {
+	if cfg == nil {
+		err = NilConfig
+		return
+	}
+
+	e = &Engine{
+		cr:     cr,
+		logger: cfg.logger,
+		client: cfg.client,
+
+		dryRun:    cfg.dryRun,
+		queueSize: cfg.queueSize,
+	}
+
+	if e.client == nil {
+		e.client = http.DefaultClient
+	}
+	if e.queueSize == 0 {
+		e.queueSize = DefaultQueueSize
+	}
+
+	if cfg.rules != nil {
+		if err = e.SetRules(*cfg.rules); err != nil {
+			return nil, err
+		}
+	}
+
+	return
+}
+
+type ConfigOption func(cfg *config)
+
+//////////////////////////////////////////////////
+
+func WithLogger(logger *slog.Logger) ConfigOption {
+	return func(cfg *config) {
+		cfg.logger = logger
+	}
+}
+
+// Sets the HTTP client used by webhook sinks (defaults to
+// http.DefaultClient).
+func WithHTTPClient(client *http.Client) ConfigOption {
+	return func(cfg *config) {
+		cfg.client = client
+	}
+}
+
+func WithRules(rules Rules) ConfigOption {
+	return func(cfg *config) {
+		cfg.rules = &rules
+	}
+}
+
+// Makes the engine only log the notifications it would send (see
+// Engine.Dispatch).
+func WithDryRun(dryRun bool) ConfigOption {
+	return func(cfg *config) {
+		cfg.dryRun = dryRun
+	}
+}
+
+// Sets the maximum number of notifications queued per sink (defaults to
+// DefaultQueueSize); further ones are dropped, until the sink catches up.
+func WithQueueSize(size int) ConfigOption {
+	return func(cfg *config) {
+		cfg.queueSize = size
+	}
+}
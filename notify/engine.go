@@ -0,0 +1,349 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	youtube "github.com/rubpy/crawly-live-youtube"
+	"github.com/rubpy/crawly/clog"
+	"github.com/rubpy/crawly/csync"
+)
+
+//////////////////////////////////////////////////
+
+// Evaluates notification rules against crawler events (see
+// youtube.StateTracker), and routes the matching ones to named sinks.
+//
+// The crawler is used to resolve the handles referred to by rules and tags
+// (e.g., "@handles") to those found in events; it may be nil when only
+// evaluating recorded events, in which case handles are compared as is.
+type Engine struct {
+	cr     *youtube.Crawler
+	logger *slog.Logger
+	client *http.Client
+
+	dryRun    bool
+	queueSize int
+	rules     csync.Value[*ruleset]
+	tags      csync.Value[map[youtube.Handle][]string]
+
+	// Notifications waiting to be sent, by sink name (see Dispatch).
+	queues  csync.Map[string, *sinkQueue]
+	pending sync.WaitGroup
+}
+
+func NewEngine(cr *youtube.Crawler, opts ...ConfigOption) (*Engine, error) {
+	var cfg config
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := validateConfig(&cfg); err != nil {
+		return nil, err
+	}
+
+	e, err := buildEngineFromConfig(cr, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+var QueueFull = errors.New("sink queue is full")
+
+//////////////////////////////////////////////////
+
+func (e *Engine) Log(ctx context.Context, params clog.Params) {
+	if e == nil || e.logger == nil {
+		return
+	}
+
+	clog.WithParams(e.logger, ctx, params)
+}
+
+// Replaces the rules (and sinks) in use.
+func (e *Engine) SetRules(rules Rules) error {
+	rs, err := rules.compile(e)
+	if err != nil {
+		return err
+	}
+
+	e.rules.Store(rs)
+	return nil
+}
+
+// Replaces the tags of every handle (which rules may match on, see
+// Condition.Tags).
+func (e *Engine) SetTags(tags map[youtube.Handle][]string) {
+	m := make(map[youtube.Handle][]string, len(tags))
+	for handle, t := range tags {
+		m[e.canonicalHandle(handle)] = append([]string(nil), t...)
+	}
+
+	e.tags.Store(m)
+}
+
+func (e *Engine) canonicalHandle(handle youtube.Handle) youtube.Handle {
+	if e.cr == nil {
+		return handle
+	}
+
+	return e.cr.CanonicalHandle(handle)
+}
+
+//////////////////////////////////////////////////
+
+// A rule matching an event, and the sinks it routes the event to.
+type Match struct {
+	Rule  string   `json:"rule"`
+	Sinks []string `json:"sinks"`
+}
+
+// Returns the rules matching event (in order), without notifying anything;
+// useful for checking rules against recorded events.
+func (e *Engine) Evaluate(event youtube.Event) (matches []Match) {
+	return e.evaluate(e.rules.Load(), event)
+}
+
+func (e *Engine) evaluate(rs *ruleset, event youtube.Event) (matches []Match) {
+	matches = []Match{}
+
+	if rs == nil {
+		return
+	}
+
+	tags := e.tags.Load()[event.State.Handle]
+	for _, rule := range rs.rules {
+		if !e.matches(&rule, event, tags) {
+			continue
+		}
+
+		matches = append(matches, Match{
+			Rule:  rule.Name,
+			Sinks: append([]string(nil), rule.Sinks...),
+		})
+
+		if rule.Final {
+			break
+		}
+	}
+
+	return
+}
+
+// Queues notifications for the sinks of every rule matching event; in dry-run
+// mode, the notifications are only logged. Every sink is notified by its own
+// goroutine (in the order of the events), so that a slow sink holds up
+// neither the other sinks nor the caller; its errors are logged. Returns the
+// matching rules, and the errors of the notifications that could not be
+// queued (see WithQueueSize).
+func (e *Engine) Dispatch(ctx context.Context, event youtube.Event) (matches []Match, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rs := e.rules.Load()
+	matches = e.evaluate(rs, event)
+	if len(matches) == 0 {
+		return
+	}
+
+	tags := e.tags.Load()[event.State.Handle]
+
+	var errs []error
+	for _, m := range matches {
+		for _, name := range m.Sinks {
+			n := newNotification(m.Rule, name, tags, event)
+
+			lp := clog.Params{
+				Message: "notify",
+				Level:   slog.LevelDebug,
+
+				Values: clog.ParamGroup{
+					"rule":   m.Rule,
+					"sink":   name,
+					"kind":   event.Kind.String(),
+					"handle": event.State.Handle.Value,
+				},
+			}
+
+			if e.dryRun {
+				lp.Level = slog.LevelInfo
+				lp.Set("dryRun", true)
+
+				e.Log(ctx, lp)
+				continue
+			}
+
+			sink, ok := rs.sinks[name]
+			if !ok {
+				// NOTE: should never happen; rules are validated beforehand.
+				lp.Err = fmt.Errorf("%w: %q", UnknownSink, name)
+			} else if !e.enqueue(name, delivery{ctx: ctx, sink: sink, n: n, lp: lp}) {
+				lp.Err = fmt.Errorf("%s: %w", name, QueueFull)
+			}
+
+			if lp.Err != nil {
+				lp.Level = slog.LevelError
+				errs = append(errs, lp.Err)
+
+				e.Log(ctx, lp)
+			}
+		}
+	}
+
+	return matches, errors.Join(errs...)
+}
+
+// Waits until every queued notification has been sent (or has failed).
+func (e *Engine) Wait() {
+	e.pending.Wait()
+}
+
+//////////////////////////////////////////////////
+
+// A notification queued for a sink, along with the log params of its
+// dispatch.
+type delivery struct {
+	ctx  context.Context
+	sink Sink
+	n    Notification
+	lp   clog.Params
+}
+
+// Notifications waiting to be sent to a sink, in order; a goroutine sending
+// them runs only while there are any.
+type sinkQueue struct {
+	mu      sync.Mutex
+	pending []delivery
+	running bool
+}
+
+// Queues a notification; returns false if the sink's queue is full.
+func (e *Engine) enqueue(name string, d delivery) bool {
+	q, _ := e.queues.LoadOrStore(name, &sinkQueue{})
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) >= e.queueSize {
+		return false
+	}
+
+	e.pending.Add(1)
+	q.pending = append(q.pending, d)
+	if !q.running {
+		q.running = true
+		go e.drain(q)
+	}
+
+	return true
+}
+
+func (e *Engine) drain(q *sinkQueue) {
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.running = false
+			q.mu.Unlock()
+
+			return
+		}
+
+		d := q.pending[0]
+		q.pending[0] = delivery{}
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+
+		if err := d.sink.Notify(d.ctx, d.n); err != nil {
+			d.lp.Level = slog.LevelError
+			d.lp.Err = fmt.Errorf("%s: %w", d.n.Sink, err)
+		}
+		e.Log(d.ctx, d.lp)
+
+		e.pending.Done()
+	}
+}
+
+//////////////////////////////////////////////////
+
+func (e *Engine) matches(rule *compiledRule, event youtube.Event, tags []string) bool {
+	cond := &rule.When
+
+	if len(cond.Events) > 0 && !contains(cond.Events, event.Kind) {
+		return false
+	}
+
+	if len(rule.handles) > 0 || len(cond.Tags) > 0 {
+		found := false
+		for _, handle := range rule.handles {
+			if e.canonicalHandle(handle) == event.State.Handle {
+				found = true
+				break
+			}
+		}
+		for _, tag := range cond.Tags {
+			if found {
+				break
+			}
+			found = contains(tags, tag)
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	// NOTE: once a stream has ended, it is only found in the previous state.
+	streams := event.State.Streams
+	if len(streams) == 0 {
+		streams = event.Previous.Streams
+	}
+
+	if rule.title != nil {
+		found := false
+		for _, stream := range streams {
+			if rule.title.MatchString(stream.Title) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if cond.MinimumViewers > 0 {
+		var viewers uint64
+		for _, stream := range streams {
+			viewers = max(viewers, stream.ConcurrentViewers)
+		}
+
+		if viewers < cond.MinimumViewers {
+			return false
+		}
+	}
+
+	if rule.hours != nil && !rule.hours.contains(event.State.Timestamp) {
+		return false
+	}
+
+	return true
+}
+
+func contains[T comparable](s []T, v T) bool {
+	for _, vv := range s {
+		if vv == v {
+			return true
+		}
+	}
+
+	return false
+}
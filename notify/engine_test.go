@@ -0,0 +1,244 @@
+package notify_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	youtube "github.com/rubpy/crawly-live-youtube"
+	"github.com/rubpy/crawly-live-youtube/notify"
+)
+
+const testChannelID = "UCSJ4gkVC6NrvII8umztf0Ow"
+
+func testEvent(kind youtube.EventKind, timestamp time.Time, streams ...youtube.LiveVideo) youtube.Event {
+	return youtube.Event{
+		Kind: kind,
+		State: youtube.LiveState{
+			Handle:    youtube.ChannelID(testChannelID),
+			Live:      len(streams) > 0,
+			Timestamp: timestamp,
+			Streams:   streams,
+		},
+	}
+}
+
+func TestEngineEvaluate(t *testing.T) {
+	// Monday.
+	noon := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	stream := youtube.LiveVideo{ID: "aaaaaaaaaaa", Title: "Tournament finals", ConcurrentViewers: 500}
+
+	sinks := map[string]notify.SinkConfig{"log": {Type: notify.SinkLog}}
+	rule := func(name string, when notify.Condition) notify.Rule {
+		return notify.Rule{Name: name, When: when, Sinks: []string{"log"}}
+	}
+
+	tests := []struct {
+		name  string
+		rules []notify.Rule
+		event youtube.Event
+		want  []string
+	}{
+		{"zero condition", []notify.Rule{rule("all", notify.Condition{})}, testEvent(youtube.EventTracked, noon), []string{"all"}},
+
+		{"handle", []notify.Rule{rule("handle", notify.Condition{Handles: []string{testChannelID}})}, testEvent(youtube.EventTracked, noon), []string{"handle"}},
+		{"other handle", []notify.Rule{rule("handle", notify.Condition{Handles: []string{"UCxxxxxxxxxxxxxxxxxxxxxx"}})}, testEvent(youtube.EventTracked, noon), []string{}},
+		{"tag", []notify.Rule{rule("tag", notify.Condition{Tags: []string{"team-a"}})}, testEvent(youtube.EventTracked, noon), []string{"tag"}},
+		{"other tag", []notify.Rule{rule("tag", notify.Condition{Tags: []string{"team-b"}})}, testEvent(youtube.EventTracked, noon), []string{}},
+		{"other handle, tag", []notify.Rule{rule("either", notify.Condition{Handles: []string{"UCxxxxxxxxxxxxxxxxxxxxxx"}, Tags: []string{"team-a"}})}, testEvent(youtube.EventTracked, noon), []string{"either"}},
+
+		{"event kind", []notify.Rule{rule("started", notify.Condition{Events: []youtube.EventKind{youtube.EventLiveStarted}})}, testEvent(youtube.EventLiveStarted, noon, stream), []string{"started"}},
+		{"other event kind", []notify.Rule{rule("started", notify.Condition{Events: []youtube.EventKind{youtube.EventLiveStarted}})}, testEvent(youtube.EventLiveEnded, noon), []string{}},
+
+		{"title", []notify.Rule{rule("title", notify.Condition{Title: "(?i)tournament"})}, testEvent(youtube.EventLiveStarted, noon, stream), []string{"title"}},
+		{"other title", []notify.Rule{rule("title", notify.Condition{Title: "(?i)rerun"})}, testEvent(youtube.EventLiveStarted, noon, stream), []string{}},
+		{"title, no streams", []notify.Rule{rule("title", notify.Condition{Title: "(?i)tournament"})}, testEvent(youtube.EventTracked, noon), []string{}},
+
+		{"viewers", []notify.Rule{rule("viewers", notify.Condition{MinimumViewers: 500})}, testEvent(youtube.EventLiveStarted, noon, stream), []string{"viewers"}},
+		{"too few viewers", []notify.Rule{rule("viewers", notify.Condition{MinimumViewers: 501})}, testEvent(youtube.EventLiveStarted, noon, stream), []string{}},
+
+		{"hours", []notify.Rule{rule("hours", notify.Condition{Hours: &notify.TimeWindow{From: "08:00", To: "22:00"}})}, testEvent(youtube.EventTracked, noon), []string{"hours"}},
+		{"outside hours", []notify.Rule{rule("hours", notify.Condition{Hours: &notify.TimeWindow{From: "13:00", To: "22:00"}})}, testEvent(youtube.EventTracked, noon), []string{}},
+		{"hours, location", []notify.Rule{rule("hours", notify.Condition{Hours: &notify.TimeWindow{From: "12:30", To: "14:00", Location: "Europe/Berlin"}})}, testEvent(youtube.EventTracked, noon), []string{"hours"}},
+		{"wrap-around, before midnight", []notify.Rule{rule("night", notify.Condition{Hours: &notify.TimeWindow{From: "22:00", To: "06:00"}})}, testEvent(youtube.EventTracked, noon.Add(11*time.Hour)), []string{"night"}},
+		{"wrap-around, after midnight", []notify.Rule{rule("night", notify.Condition{Hours: &notify.TimeWindow{From: "22:00", To: "06:00"}})}, testEvent(youtube.EventTracked, noon.Add(15*time.Hour)), []string{"night"}},
+		{"wrap-around, outside", []notify.Rule{rule("night", notify.Condition{Hours: &notify.TimeWindow{From: "22:00", To: "06:00"}})}, testEvent(youtube.EventTracked, noon), []string{}},
+		// NOTE: the window opened on Sunday.
+		{"wrap-around, weekday", []notify.Rule{rule("night", notify.Condition{Hours: &notify.TimeWindow{From: "22:00", To: "06:00", Weekdays: []string{"sun"}}})}, testEvent(youtube.EventTracked, noon.Add(-9*time.Hour)), []string{"night"}},
+		{"wrap-around, other weekday", []notify.Rule{rule("night", notify.Condition{Hours: &notify.TimeWindow{From: "22:00", To: "06:00", Weekdays: []string{"mon"}}})}, testEvent(youtube.EventTracked, noon.Add(-9*time.Hour)), []string{}},
+
+		{"in order", []notify.Rule{rule("first", notify.Condition{}), rule("second", notify.Condition{})}, testEvent(youtube.EventTracked, noon), []string{"first", "second"}},
+		{"final", []notify.Rule{rule("other", notify.Condition{Events: []youtube.EventKind{youtube.EventLiveEnded}}), {Name: "final", Sinks: []string{"log"}, Final: true}, rule("skipped", notify.Condition{})}, testEvent(youtube.EventTracked, noon), []string{"final"}},
+	}
+
+	for _, tt := range tests {
+		e, err := notify.NewEngine(nil, notify.WithRules(notify.Rules{Sinks: sinks, Rules: tt.rules}))
+		if err != nil {
+			t.Fatalf("%s: NewEngine: %v", tt.name, err)
+		}
+		e.SetTags(map[youtube.Handle][]string{youtube.ChannelID(testChannelID): {"team-a"}})
+
+		got := []string{}
+		for _, m := range e.Evaluate(tt.event) {
+			got = append(got, m.Rule)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got matches %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEngineDryRun(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	e, err := notify.NewEngine(nil,
+		notify.WithLogger(slog.New(slog.NewJSONHandler(&logs, nil))),
+		notify.WithDryRun(true),
+		notify.WithRules(notify.Rules{
+			Sinks: map[string]notify.SinkConfig{"hook": {Type: notify.SinkWebhook, URL: srv.URL}},
+			Rules: []notify.Rule{{Name: "all", Sinks: []string{"hook"}}},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+
+	matches, err := e.Dispatch(context.Background(), testEvent(youtube.EventTracked, time.Now()))
+	e.Wait()
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("got %v, %v", matches, err)
+	}
+
+	if requests != 0 {
+		t.Fatalf("got %d webhook requests in dry-run mode", requests)
+	}
+	if !strings.Contains(logs.String(), `"dryRun":true`) {
+		t.Fatalf("dry-run notification not logged: %s", logs.String())
+	}
+}
+
+func TestEngineWebhook(t *testing.T) {
+	received := make(chan notify.Notification, 10)
+	release := make(chan struct{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		var n notify.Notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil || r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		received <- n
+	})
+	mux.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	defer close(release)
+
+	var logs bytes.Buffer
+	e, err := notify.NewEngine(nil,
+		notify.WithLogger(slog.New(slog.NewJSONHandler(&logs, nil))),
+		notify.WithHTTPClient(srv.Client()),
+		notify.WithRules(notify.Rules{
+			Sinks: map[string]notify.SinkConfig{
+				"ok":    {Type: notify.SinkWebhook, URL: srv.URL + "/ok", Headers: map[string]string{"X-Token": "secret"}},
+				"error": {Type: notify.SinkWebhook, URL: srv.URL + "/error"},
+				"slow":  {Type: notify.SinkWebhook, URL: srv.URL + "/slow", Timeout: 50 * time.Millisecond},
+			},
+			Rules: []notify.Rule{{Name: "all", Sinks: []string{"slow", "error", "ok"}}},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := e.Dispatch(context.Background(), testEvent(youtube.EventTracked, start)); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+
+	// NOTE: the slow sink holds up neither the caller nor the other sinks.
+	select {
+	case n := <-received:
+		if n.Rule != "all" || n.Sink != "ok" || n.Kind != youtube.EventTracked || n.Previous != nil {
+			t.Fatalf("got notification %+v", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("ok sink not notified")
+	}
+
+	e.Wait()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("took %s, despite the slow sink's timeout", elapsed)
+	}
+
+	out := logs.String()
+	if !strings.Contains(out, notify.UnexpectedStatus.Error()+": 500") {
+		t.Errorf("non-2xx response not logged: %s", out)
+	}
+	if !strings.Contains(out, context.DeadlineExceeded.Error()) {
+		t.Errorf("timeout not logged: %s", out)
+	}
+}
+
+func TestEngineQueueFull(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+
+	e, err := notify.NewEngine(nil,
+		notify.WithHTTPClient(srv.Client()),
+		notify.WithQueueSize(1),
+		notify.WithRules(notify.Rules{
+			Sinks: map[string]notify.SinkConfig{"hook": {Type: notify.SinkWebhook, URL: srv.URL}},
+			Rules: []notify.Rule{{Name: "all", Sinks: []string{"hook"}}},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+
+	var errs int
+	for i := 0; i < 3; i++ {
+		if _, err := e.Dispatch(context.Background(), testEvent(youtube.EventTracked, time.Now())); err != nil {
+			if !errors.Is(err, notify.QueueFull) {
+				t.Fatalf("Dispatch: got %v, want QueueFull", err)
+			}
+			errs++
+		}
+	}
+
+	close(release)
+	e.Wait()
+
+	// NOTE: the first notification may or may not have left the queue yet.
+	if errs < 1 || errs > 2 {
+		t.Fatalf("got %d QueueFull errors, want 1 or 2", errs)
+	}
+}
@@ -0,0 +1,253 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	youtube "github.com/rubpy/crawly-live-youtube"
+)
+
+//////////////////////////////////////////////////
+
+// Notification routing: named sinks, and the rules deciding which events are
+// sent to which of them. Every matching rule notifies its sinks (in order),
+// unless an earlier matching rule is Final.
+type Rules struct {
+	Sinks map[string]SinkConfig `json:"sinks"`
+	Rules []Rule                `json:"rules"`
+}
+
+type Rule struct {
+	Name  string    `json:"name"`
+	When  Condition `json:"when"`
+	Sinks []string  `json:"sinks"`
+
+	// Stop evaluating the rules that follow once this one matches.
+	Final bool `json:"final,omitempty"`
+}
+
+// Conditions of a rule; all of the non-empty ones have to be met (a zero
+// Condition matches every event).
+type Condition struct {
+	// Handles (channel IDs, channel URLs, "@handles" or playlist IDs) and
+	// tags (see Engine.SetTags), of which the event's handle has to match
+	// any.
+	Handles []string `json:"handles,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+
+	// Kinds of events (any of them).
+	Events []youtube.EventKind `json:"events,omitempty"`
+
+	// Regular expression (RE2 syntax) matched against the titles of the
+	// event's streams (any of them); e.g., "(?i)tournament".
+	Title string `json:"title,omitempty"`
+
+	// Minimum number of concurrent viewers of the event's most watched live
+	// stream.
+	MinimumViewers uint64 `json:"minimum_viewers,omitempty"`
+
+	// Time of day (of the event's timestamp) within which the rule applies.
+	Hours *TimeWindow `json:"hours,omitempty"`
+}
+
+// A daily window of time, from From (inclusive) to To (exclusive), both given
+// as "15:04"; it wraps around midnight if To is not after From.
+type TimeWindow struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Days of the week (e.g., "mon", "sat") on which the window is open
+	// (every day, if empty); the day is that on which the window opened.
+	Weekdays []string `json:"weekdays,omitempty"`
+
+	// IANA time zone name (e.g., "Europe/Berlin"); defaults to UTC.
+	Location string `json:"location,omitempty"`
+}
+
+var (
+	InvalidRules = errors.New("invalid notification rules")
+	UnknownSink  = errors.New("unknown sink")
+)
+
+// Checks the rules (and sinks) for errors, e.g. invalid patterns or rules
+// referring to an unknown sink.
+func (r Rules) Validate() error {
+	_, err := r.compile(nil)
+
+	return err
+}
+
+//////////////////////////////////////////////////
+
+type ruleset struct {
+	rules []compiledRule
+	sinks map[string]Sink
+}
+
+type compiledRule struct {
+	Rule
+
+	handles []youtube.Handle
+	title   *regexp.Regexp
+	hours   *timeWindow
+}
+
+type timeWindow struct {
+	from, to time.Duration // (since midnight)
+	weekdays map[time.Weekday]struct{}
+	loc      *time.Location
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func (r Rules) compile(e *Engine) (rs *ruleset, err error) {
+	var errs []error
+	invalid := func(field string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s: %s", InvalidRules, field, fmt.Sprintf(format, args...)))
+	}
+
+	rs = &ruleset{
+		sinks: make(map[string]Sink, len(r.Sinks)),
+	}
+
+	for name, sc := range r.Sinks {
+		sink, err := sc.build(e)
+		if err != nil {
+			invalid(fmt.Sprintf("sinks[%q]", name), "%v", err)
+			continue
+		}
+
+		rs.sinks[name] = sink
+	}
+
+	for idx, rule := range r.Rules {
+		field := fmt.Sprintf("rules[%d]", idx)
+		if rule.Name != "" {
+			field = fmt.Sprintf("rules[%q]", rule.Name)
+		}
+
+		c := compiledRule{Rule: rule}
+
+		if len(rule.Sinks) == 0 {
+			invalid(field+".sinks", "must not be empty")
+		}
+		for _, name := range rule.Sinks {
+			if _, ok := r.Sinks[name]; !ok {
+				invalid(field+".sinks", "%v: %q", UnknownSink, name)
+			}
+		}
+
+		for _, s := range rule.When.Handles {
+			handle, err := youtube.ParseHandle(s)
+			if err != nil {
+				invalid(field+".when.handles", "%q: %v", s, err)
+				continue
+			}
+
+			c.handles = append(c.handles, handle)
+		}
+
+		if rule.When.Title != "" {
+			if c.title, err = regexp.Compile(rule.When.Title); err != nil {
+				invalid(field+".when.title", "%v", err)
+			}
+		}
+
+		if rule.When.Hours != nil {
+			if c.hours, err = rule.When.Hours.compile(); err != nil {
+				invalid(field+".when.hours", "%v", err)
+			}
+		}
+
+		rs.rules = append(rs.rules, c)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return rs, nil
+}
+
+func (w TimeWindow) compile() (tw *timeWindow, err error) {
+	clock := func(s string) (time.Duration, error) {
+		t, err := time.Parse("15:04", s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time of day %q (want \"15:04\")", s)
+		}
+
+		return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+	}
+
+	tw = &timeWindow{
+		loc: time.UTC,
+	}
+
+	if tw.from, err = clock(w.From); err != nil {
+		return nil, err
+	}
+	if tw.to, err = clock(w.To); err != nil {
+		return nil, err
+	}
+
+	if w.Location != "" {
+		if tw.loc, err = time.LoadLocation(w.Location); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(w.Weekdays) > 0 {
+		tw.weekdays = make(map[time.Weekday]struct{}, len(w.Weekdays))
+		for _, name := range w.Weekdays {
+			day, ok := weekdayNames[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("invalid weekday %q", name)
+			}
+
+			tw.weekdays[day] = struct{}{}
+		}
+	}
+
+	return tw, nil
+}
+
+// Reports whether t falls within the window.
+func (tw *timeWindow) contains(t time.Time) bool {
+	t = t.In(tw.loc)
+	y, m, d := t.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, tw.loc)
+	since := t.Sub(midnight)
+
+	var open bool
+	switch {
+	case tw.from < tw.to:
+		open = since >= tw.from && since < tw.to
+	case since >= tw.from:
+		open = true
+	case since < tw.to:
+		// NOTE: the window opened on the previous day.
+		open = true
+		midnight = midnight.AddDate(0, 0, -1)
+	}
+
+	if !open {
+		return false
+	}
+	if tw.weekdays == nil {
+		return true
+	}
+
+	_, ok := tw.weekdays[midnight.Weekday()]
+	return ok
+}
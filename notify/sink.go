@@ -0,0 +1,213 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	youtube "github.com/rubpy/crawly-live-youtube"
+	"github.com/rubpy/crawly/clog"
+)
+
+//////////////////////////////////////////////////
+
+// What a sink receives for each matching rule: the event, with Previous left
+// out (nil) if the handle had no previous state (e.g., for EventTracked).
+type Notification struct {
+	Rule string   `json:"rule"`
+	Sink string   `json:"sink"`
+	Tags []string `json:"tags,omitempty"`
+
+	Kind     youtube.EventKind  `json:"kind"`
+	State    youtube.LiveState  `json:"state"`
+	Previous *youtube.LiveState `json:"previous,omitempty"`
+}
+
+func newNotification(rule string, sink string, tags []string, event youtube.Event) Notification {
+	n := Notification{
+		Rule: rule,
+		Sink: sink,
+		Tags: tags,
+
+		Kind:  event.Kind,
+		State: event.State,
+	}
+	if event.Previous.Handle.Valid() {
+		n.Previous = &event.Previous
+	}
+
+	return n
+}
+
+type Sink interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+//////////////////////////////////////////////////
+
+type SinkType uint
+
+const (
+	// Logs notifications via the engine's logger.
+	SinkLog SinkType = (iota + 1)
+	// POSTs notifications (as JSON) to a URL.
+	SinkWebhook
+)
+
+var sinkTypeNames = map[SinkType]string{
+	SinkLog:     "log",
+	SinkWebhook: "webhook",
+}
+
+var InvalidSinkType = errors.New("invalid sink type")
+
+func (t SinkType) String() string {
+	return sinkTypeNames[t]
+}
+
+func (t SinkType) MarshalText() ([]byte, error) {
+	name, ok := sinkTypeNames[t]
+	if !ok {
+		return nil, InvalidSinkType
+	}
+
+	return []byte(name), nil
+}
+
+func (t *SinkType) UnmarshalText(b []byte) error {
+	for typ, name := range sinkTypeNames {
+		if string(b) == name {
+			*t = typ
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %q", InvalidSinkType, b)
+}
+
+type SinkConfig struct {
+	Type SinkType `json:"type"`
+
+	// (Webhook only.) Timeout defaults to DefaultWebhookTimeout.
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Timeout time.Duration     `json:"timeout,omitempty"`
+}
+
+var DefaultWebhookTimeout = 10 * time.Second
+
+func (sc SinkConfig) build(e *Engine) (sink Sink, err error) {
+	switch sc.Type {
+	case SinkLog:
+		return &logSink{e: e}, nil
+
+	case SinkWebhook:
+		u, err := url.Parse(sc.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL %q", sc.URL)
+		}
+		if sc.Timeout < 0 {
+			return nil, fmt.Errorf("timeout must not be negative (got %s)", sc.Timeout)
+		}
+
+		ws := &WebhookSink{
+			URL:     sc.URL,
+			Headers: sc.Headers,
+			Timeout: sc.Timeout,
+		}
+		if ws.Timeout == 0 {
+			ws.Timeout = DefaultWebhookTimeout
+		}
+		if e != nil {
+			ws.Client = e.client
+		}
+
+		return ws, nil
+	}
+
+	return nil, fmt.Errorf("%w: %v", InvalidSinkType, sc.Type)
+}
+
+//////////////////////////////////////////////////
+
+type logSink struct {
+	e *Engine
+}
+
+func (s *logSink) Notify(ctx context.Context, n Notification) error {
+	s.e.Log(ctx, clog.Params{
+		Message: "notification",
+		Level:   slog.LevelInfo,
+
+		Values: clog.ParamGroup{
+			"rule":       n.Rule,
+			"sink":       n.Sink,
+			"kind":       n.Kind.String(),
+			"handle":     n.State.Handle.Value,
+			"liveVideos": n.State.LiveVideos,
+		},
+	})
+
+	return nil
+}
+
+//////////////////////////////////////////////////
+
+var UnexpectedStatus = errors.New("unexpected status code")
+
+// POSTs each notification as JSON to URL, expecting a 2xx response.
+type WebhookSink struct {
+	URL     string
+	Headers map[string]string
+	Timeout time.Duration
+
+	// Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+func (s *WebhookSink) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %d", UnexpectedStatus, resp.StatusCode)
+	}
+
+	return nil
+}
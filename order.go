@@ -2,6 +2,7 @@ package youtube
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -54,6 +55,10 @@ func (cr *Crawler) orderHandler(ctx context.Context, order *crawly.Order, result
 			lp.Err = err
 			cr.Log(ctx, lp)
 
+			if errors.Is(err, ChannelTerminated) {
+				// NOTE: there is nothing left to resolve (or track).
+				return crawly.InvalidHandle
+			}
 			if err != nil {
 				return err
 			}
@@ -62,6 +67,8 @@ func (cr *Crawler) orderHandler(ctx context.Context, order *crawly.Order, result
 		if channelID == "" {
 			return InvalidChannelID
 		}
+		cr.moveOverride(handle, ChannelID(channelID))
+
 		handle = Handle{
 			Type:  HandleChannelID,
 			Value: channelID,
@@ -75,6 +82,11 @@ func (cr *Crawler) orderHandler(ctx context.Context, order *crawly.Order, result
 			return crawly.InvalidHandle
 		}
 
+	case HandlePlaylistID:
+		if !IsValidPlaylistID(handle.Value) {
+			return crawly.InvalidHandle
+		}
+
 	default:
 		return crawly.InvalidHandle
 	}
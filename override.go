@@ -0,0 +1,231 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+//////////////////////////////////////////////////
+
+// Per-handle replacement for a subset of CrawlerSettings. Tier refers to one
+// of CrawlerSettings.Tiers (applied first); the remaining non-nil fields are
+// applied on top of it.
+type SettingsOverride struct {
+	Tier string `json:"tier,omitempty"`
+
+	StopAfterLiveVideos *int `json:"stop_after_live_videos,omitempty"`
+
+	MinimumFetchChannelFeedDelay *time.Duration `json:"minimum_fetch_channel_feed_delay,omitempty"`
+	MinimumCheckVideoDelay       *time.Duration `json:"minimum_check_video_delay,omitempty"`
+	MaximumVideoAge              *time.Duration `json:"maximum_video_age,omitempty"`
+
+	CandidateSources *CandidateSources `json:"candidate_sources,omitempty"`
+	UploadsDepth     *int              `json:"uploads_depth,omitempty"`
+
+	ExcludePremieres *bool `json:"exclude_premieres,omitempty"`
+
+	// Replaces CrawlerSettings.Filter as a whole.
+	Filter *StreamFilter `json:"filter,omitempty"`
+}
+
+var (
+	UnknownSettingsTier = errors.New("unknown settings tier")
+	NestedSettingsTier  = errors.New("tier cannot refer to another tier")
+)
+
+func (o SettingsOverride) Empty() bool {
+	return o == SettingsOverride{}
+}
+
+func (o SettingsOverride) apply(settings CrawlerSettings) CrawlerSettings {
+	if o.StopAfterLiveVideos != nil {
+		settings.StopAfterLiveVideos = *o.StopAfterLiveVideos
+	}
+	if o.MinimumFetchChannelFeedDelay != nil {
+		settings.MinimumFetchChannelFeedDelay = *o.MinimumFetchChannelFeedDelay
+	}
+	if o.MinimumCheckVideoDelay != nil {
+		settings.MinimumCheckVideoDelay = *o.MinimumCheckVideoDelay
+	}
+	if o.MaximumVideoAge != nil {
+		settings.MaximumVideoAge = *o.MaximumVideoAge
+	}
+	if o.CandidateSources != nil {
+		settings.CandidateSources = *o.CandidateSources
+	}
+	if o.UploadsDepth != nil {
+		settings.UploadsDepth = *o.UploadsDepth
+	}
+	if o.ExcludePremieres != nil {
+		settings.ExcludePremieres = *o.ExcludePremieres
+	}
+	if o.Filter != nil {
+		settings.Filter = o.Filter
+	}
+
+	return settings
+}
+
+// Returns settings with the override (and the tier it refers to) applied.
+func (o SettingsOverride) Apply(settings CrawlerSettings) CrawlerSettings {
+	if o.Tier != "" {
+		if tier, ok := settings.Tiers[o.Tier]; ok {
+			settings = tier.apply(settings)
+		}
+	}
+
+	return o.apply(settings)
+}
+
+// Checks the override's fields with the same rules as
+// CrawlerSettings.Validate.
+func (o SettingsOverride) Validate() error {
+	return o.apply(DefaultSettings).Validate()
+}
+
+func (settings CrawlerSettings) validateTiers() (errs SettingsErrors) {
+	for name, tier := range settings.Tiers {
+		field := fmt.Sprintf("Tiers[%q]", name)
+
+		if tier.Tier != "" {
+			errs = append(errs, &SettingsError{field + ".Tier", tier.Tier, NestedSettingsTier.Error()})
+		}
+
+		if err := tier.Validate(); err != nil {
+			for _, e := range err.(SettingsErrors) {
+				errs = append(errs, &SettingsError{field + "." + e.Field, e.Value, e.Reason})
+			}
+		}
+	}
+
+	return
+}
+
+//////////////////////////////////////////////////
+
+// A settings override attached to a handle, along with the effective settings
+// it results in (recomputed whenever either of them changes, rather than on
+// every pass).
+type handleSettings struct {
+	override SettingsOverride
+	settings CrawlerSettings
+}
+
+// Returns the settings override attached to a handle.
+func (cr *Crawler) HandleSettings(handle Handle) (override SettingsOverride, ok bool) {
+	hs, ok := cr.overrides.Load(cr.canonicalHandle(handle))
+	return hs.override, ok
+}
+
+// Attaches a settings override to a handle (replacing any previous one); an
+// empty override detaches it.
+func (cr *Crawler) SetHandleSettings(handle Handle, override SettingsOverride) error {
+	cr.settingsMu.Lock()
+	defer cr.settingsMu.Unlock()
+
+	if err := cr.validateOverride(override); err != nil {
+		return err
+	}
+
+	handle = cr.canonicalHandle(handle)
+	if override.Empty() {
+		cr.overrides.Delete(handle)
+	} else {
+		cr.overrides.Store(handle, handleSettings{
+			override: override,
+			settings: override.Apply(cr.loadSettings()).Normalized(),
+		})
+	}
+
+	return nil
+}
+
+// Same as Track, but also attaches a settings override to the handle.
+func (cr *Crawler) TrackWithSettings(ctx context.Context, handle Handle, override SettingsOverride) (tracked bool, err error) {
+	if err = cr.SetHandleSettings(handle, override); err != nil {
+		return
+	}
+
+	return cr.Track(ctx, handle)
+}
+
+// Returns the effective settings used for a handle (i.e., normalized
+// settings with its override applied).
+func (cr *Crawler) EffectiveHandleSettings(handle Handle) CrawlerSettings {
+	return cr.loadHandleSettings(cr.canonicalHandle(handle))
+}
+
+func (cr *Crawler) loadHandleSettings(handle Handle) CrawlerSettings {
+	hs, ok := cr.overrides.Load(handle)
+	if !ok {
+		return cr.loadEffectiveSettings()
+	}
+
+	return hs.settings
+}
+
+// Recomputes the effective settings of every handle with an override (after
+// the crawler's settings have changed). Must be called with settingsMu held.
+func (cr *Crawler) refreshHandleSettings(settings CrawlerSettings) {
+	cr.overrides.Range(func(handle Handle, hs handleSettings) bool {
+		hs.settings = hs.override.Apply(settings).Normalized()
+		cr.overrides.Store(handle, hs)
+
+		return true
+	})
+}
+
+func (cr *Crawler) validateOverride(override SettingsOverride) error {
+	if err := override.Validate(); err != nil {
+		return err
+	}
+
+	if override.Tier != "" {
+		if _, ok := cr.loadSettings().Tiers[override.Tier]; !ok {
+			return fmt.Errorf("%w: %q", UnknownSettingsTier, override.Tier)
+		}
+	}
+
+	return nil
+}
+
+// Checks that every tier referred to by a handle's override still exists in
+// settings (rather than letting those handles silently fall back to the
+// crawler's settings). Must be called with settingsMu held.
+func (cr *Crawler) validateTierReferences(settings CrawlerSettings) error {
+	var errs SettingsErrors
+
+	cr.overrides.Range(func(handle Handle, hs handleSettings) bool {
+		if tier := hs.override.Tier; tier != "" {
+			if _, ok := settings.Tiers[tier]; !ok {
+				errs = append(errs, &SettingsError{fmt.Sprintf("Tiers[%q]", tier), handle, "must not be removed while a handle refers to it"})
+			}
+		}
+
+		return true
+	})
+
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool {
+			return errs[i].Error() < errs[j].Error()
+		})
+
+		return errs
+	}
+
+	return nil
+}
+
+// Moves the override attached to a channel URL over to its channel ID (once
+// the URL has been resolved).
+func (cr *Crawler) moveOverride(from Handle, to Handle) {
+	cr.settingsMu.Lock()
+	defer cr.settingsMu.Unlock()
+
+	if hs, ok := cr.overrides.LoadAndDelete(from); ok {
+		cr.overrides.Store(to, hs)
+	}
+}
@@ -0,0 +1,90 @@
+package youtube_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rubpy/crawly"
+	youtube "github.com/rubpy/crawly-live-youtube"
+	"github.com/rubpy/crawly-live-youtube/youtubetest"
+)
+
+func TestHandleSettings(t *testing.T) {
+	ctx := context.Background()
+
+	srv := youtubetest.NewServer()
+	defer srv.Close()
+
+	settings := youtube.DefaultSettings
+	settings.MaximumTrackingAttempts = 2
+	settings.Tiers = map[string]youtube.SettingsOverride{
+		"fast": {MinimumCheckVideoDelay: ptr(10 * time.Second)},
+	}
+
+	clk := youtubetest.NewClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	srv.Now = clk.Now
+
+	cr, err := srv.NewCrawler(ctx, youtube.WithClock(clk), youtube.WithSettings(settings))
+	if err != nil {
+		t.Fatalf("NewCrawler: %v", err)
+	}
+
+	handle := youtube.ChannelID("UCSJ4gkVC6NrvII8umztf0Ow")
+	if err := cr.SetHandleSettings(handle, youtube.SettingsOverride{Tier: "fast"}); err != nil {
+		t.Fatalf("SetHandleSettings: %v", err)
+	}
+	if got := cr.EffectiveHandleSettings(handle).MinimumCheckVideoDelay; got != 10*time.Second {
+		t.Fatalf("got MinimumCheckVideoDelay %s, want 10s", got)
+	}
+
+	settings.Tiers = map[string]youtube.SettingsOverride{
+		"fast": {MinimumCheckVideoDelay: ptr(20 * time.Second)},
+	}
+	if err := cr.SetSettings(settings); err != nil {
+		t.Fatalf("SetSettings: %v", err)
+	}
+	if got := cr.EffectiveHandleSettings(handle).MinimumCheckVideoDelay; got != 20*time.Second {
+		t.Fatalf("got MinimumCheckVideoDelay %s after SetSettings, want 20s", got)
+	}
+
+	renamed := settings
+	renamed.Tiers = map[string]youtube.SettingsOverride{
+		"quick": {MinimumCheckVideoDelay: ptr(20 * time.Second)},
+	}
+	if err := cr.SetSettings(renamed); !errors.Is(err, youtube.InvalidSettings) {
+		t.Fatalf("SetSettings with a referenced tier removed: got %v, want InvalidSettings", err)
+	}
+	if _, ok := cr.Settings().Tiers["fast"]; !ok {
+		t.Fatalf("settings changed despite the error")
+	}
+
+	// NOTE: the override is dropped along with the entity, once crawly gives
+	// up on it.
+	srv.Handle("/feeds/videos.xml", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+
+	e := &crawly.Entity{Handle: handle}
+	if err := youtube.EntityHandler(cr, ctx, e, &crawly.TrackingResult{}); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if _, ok := cr.HandleSettings(handle); !ok {
+		t.Fatalf("override dropped before the last attempt")
+	}
+
+	e.Attempt++
+	clk.Advance(time.Hour)
+	if err := youtube.EntityHandler(cr, ctx, e, &crawly.TrackingResult{}); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if _, ok := cr.HandleSettings(handle); ok {
+		t.Fatalf("override kept after the last attempt")
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
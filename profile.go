@@ -0,0 +1,360 @@
+package youtube
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+//////////////////////////////////////////////////
+
+// Kind of (scraping) request made by the crawler.
+type Endpoint uint
+
+const (
+	EndpointUnknown Endpoint = iota
+	EndpointChannelFeed
+	EndpointChannelIndex
+	EndpointThumbnail
+	EndpointChannelStreams
+)
+
+var endpointNames = map[Endpoint]string{
+	EndpointChannelFeed:    "channel_feed",
+	EndpointChannelIndex:   "channel_index",
+	EndpointThumbnail:      "thumbnail",
+	EndpointChannelStreams: "channel_streams",
+}
+
+var InvalidEndpoint = errors.New("invalid endpoint")
+
+func (e Endpoint) String() string {
+	if name, ok := endpointNames[e]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+func (e Endpoint) MarshalText() ([]byte, error) {
+	name, ok := endpointNames[e]
+	if !ok {
+		return nil, InvalidEndpoint
+	}
+
+	return []byte(name), nil
+}
+
+func (e *Endpoint) UnmarshalText(b []byte) error {
+	for endpoint, name := range endpointNames {
+		if string(b) == name {
+			*e = endpoint
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %q", InvalidEndpoint, b)
+}
+
+//////////////////////////////////////////////////
+
+// Determines how requests get past YouTube's consent wall.
+type ConsentStrategy uint
+
+const (
+	// Sends RequestProfile.ConsentCookie (or DefaultConsentCookie) with every
+	// request.
+	ConsentStatic ConsentStrategy = iota
+	// Sends a SOCS cookie generated for the profile's language and the current
+	// date.
+	ConsentGenerated
+	// Sends no consent cookie; relies on the client's cookie jar instead
+	// (e.g., populated via cclient.Client.SetCookies).
+	ConsentCookieJar
+)
+
+var consentStrategyNames = map[ConsentStrategy]string{
+	ConsentStatic:    "static",
+	ConsentGenerated: "generated",
+	ConsentCookieJar: "cookie_jar",
+}
+
+var InvalidConsentStrategy = errors.New("invalid consent strategy")
+
+func (s ConsentStrategy) String() string {
+	if name, ok := consentStrategyNames[s]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+func (s ConsentStrategy) MarshalText() ([]byte, error) {
+	name, ok := consentStrategyNames[s]
+	if !ok {
+		return nil, InvalidConsentStrategy
+	}
+
+	return []byte(name), nil
+}
+
+func (s *ConsentStrategy) UnmarshalText(b []byte) error {
+	for strategy, name := range consentStrategyNames {
+		if string(b) == name {
+			*s = strategy
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %q", InvalidConsentStrategy, b)
+}
+
+// Consent cookie sent by ConsentStatic when no other one is configured.
+var DefaultConsentCookie = "SOCS=CAESEwgDEgk0ODE3Nzk3MjQaAmVuIAEaBgiA_LyaBg"
+
+//////////////////////////////////////////////////
+
+// Headers sent along with each request for a given user-agent profile name
+// (see RequestProfile.UserAgent).
+var UserAgentProfiles = map[string]http.Header{
+	"chrome": {
+		"User-Agent":         {"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/118.0.0.0 Safari/537.36"},
+		"Sec-Ch-Ua":          {`"Chromium";v="118", "Google Chrome";v="118", "Not=A?Brand";v="99"`},
+		"Sec-Ch-Ua-Mobile":   {"?0"},
+		"Sec-Ch-Ua-Platform": {`"Windows"`},
+	},
+	"firefox": {
+		"User-Agent": {"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:118.0) Gecko/20100101 Firefox/118.0"},
+	},
+	"safari": {
+		"User-Agent": {"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15"},
+	},
+}
+
+// Describes the identity the crawler presents to YouTube: consent handling,
+// locale and headers.
+type RequestProfile struct {
+	Consent ConsentStrategy `json:"consent"`
+	// Full cookie (e.g., "SOCS=..."), used by ConsentStatic.
+	ConsentCookie string `json:"consent_cookie"`
+
+	// BCP 47 language tag (e.g., "de-DE"); sent as Accept-Language, and as the
+	// "hl" parameter of channel page requests.
+	Language string `json:"language"`
+	// Two-letter region code (e.g., "DE"); sent as the "gl" parameter of
+	// channel page requests.
+	Region string `json:"region"`
+
+	// Either the name of one of UserAgentProfiles, or a literal User-Agent
+	// string (empty leaves the client's default).
+	UserAgent string `json:"user_agent"`
+
+	// Extra headers sent to a given endpoint (applied last).
+	Headers map[Endpoint]http.Header `json:"headers"`
+}
+
+// Matches the crawler's historical behavior (static consent cookie only).
+var DefaultRequestProfile = RequestProfile{
+	Consent: ConsentStatic,
+}
+
+var InvalidRequestProfile = errors.New("invalid request profile")
+
+func (p RequestProfile) Validate() error {
+	if _, ok := consentStrategyNames[p.Consent]; !ok {
+		return fmt.Errorf("%w: %w: %d", InvalidRequestProfile, InvalidConsentStrategy, p.Consent)
+	}
+
+	if p.Language != "" && !isValidLanguageTag(p.Language) {
+		return fmt.Errorf("%w: invalid language: %q", InvalidRequestProfile, p.Language)
+	}
+
+	if p.Region != "" && !isValidRegionCode(p.Region) {
+		return fmt.Errorf("%w: invalid region: %q", InvalidRequestProfile, p.Region)
+	}
+
+	for endpoint := range p.Headers {
+		if _, ok := endpointNames[endpoint]; !ok {
+			return fmt.Errorf("%w: %w: %d", InvalidRequestProfile, InvalidEndpoint, endpoint)
+		}
+	}
+
+	return nil
+}
+
+func isValidLanguageTag(s string) bool {
+	for i, part := range strings.Split(s, "-") {
+		if len(part) == 0 || len(part) > 8 || (i == 0 && len(part) > 3) {
+			return false
+		}
+
+		for _, r := range part {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func isValidRegionCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns the primary language subtag (e.g., "en" for "en-GB").
+func (p RequestProfile) primaryLanguage() string {
+	lang, _, _ := strings.Cut(p.Language, "-")
+
+	return strings.ToLower(lang)
+}
+
+// Sets locale query params on u (only for endpoints which honor them).
+func (p RequestProfile) applyQuery(endpoint Endpoint, u *url.URL) {
+	if endpoint != EndpointChannelIndex && endpoint != EndpointChannelStreams {
+		return
+	}
+	if p.Language == "" && p.Region == "" {
+		return
+	}
+
+	q := u.Query()
+	if p.Language != "" {
+		q.Set("hl", p.Language)
+	}
+	if p.Region != "" {
+		q.Set("gl", strings.ToUpper(p.Region))
+	}
+	u.RawQuery = q.Encode()
+}
+
+// Returns the headers to be sent with a request to the given endpoint.
+func (p RequestProfile) header(endpoint Endpoint, now time.Time) http.Header {
+	h := http.Header{}
+
+	if ua := p.UserAgent; ua != "" {
+		if profile, ok := UserAgentProfiles[ua]; ok {
+			for k, v := range profile {
+				h[http.CanonicalHeaderKey(k)] = v
+			}
+		} else {
+			h.Set("User-Agent", ua)
+		}
+	}
+
+	if p.Language != "" {
+		acceptLanguage := p.Language
+		if lang := p.primaryLanguage(); lang != strings.ToLower(p.Language) {
+			acceptLanguage += "," + lang + ";q=0.9"
+		}
+
+		h.Set("Accept-Language", acceptLanguage)
+	}
+
+	switch p.Consent {
+	case ConsentStatic:
+		cookie := p.ConsentCookie
+		if cookie == "" {
+			cookie = DefaultConsentCookie
+		}
+
+		h.Set("Cookie", cookie)
+
+	case ConsentGenerated:
+		h.Set("Cookie", generateConsentCookie(p.primaryLanguage(), now))
+	}
+
+	for k, v := range p.Headers[endpoint] {
+		h[http.CanonicalHeaderKey(k)] = v
+	}
+
+	return h
+}
+
+//////////////////////////////////////////////////
+
+func (cr *Crawler) RequestProfile() RequestProfile {
+	return cr.loadRequestProfile()
+}
+
+// Replaces the request profile (effective for subsequent requests).
+func (cr *Crawler) SetRequestProfile(profile RequestProfile) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	cr.storeRequestProfile(profile)
+	return nil
+}
+
+func (cr *Crawler) loadRequestProfile() RequestProfile {
+	return cr.requestProfile.Load()
+}
+
+func (cr *Crawler) storeRequestProfile(profile RequestProfile) {
+	cr.requestProfile.Store(profile)
+}
+
+//////////////////////////////////////////////////
+
+// Generates a SOCS consent cookie ("accept all") for the given language,
+// dated to the given day.
+func generateConsentCookie(language string, now time.Time) string {
+	if language == "" {
+		language = "en"
+	}
+
+	day := now.UTC().Truncate(24 * time.Hour).Unix()
+
+	var consent []byte
+	consent = appendProtoVarint(consent, 1, 3)
+	consent = appendProtoBytes(consent, 2, []byte("481779724"))
+	consent = appendProtoBytes(consent, 3, []byte(language))
+	consent = appendProtoVarint(consent, 4, 1)
+
+	var timestamp []byte
+	timestamp = appendProtoVarint(timestamp, 1, uint64(day))
+
+	var b []byte
+	b = appendProtoVarint(b, 1, 1)
+	b = appendProtoBytes(b, 2, consent)
+	b = appendProtoBytes(b, 3, timestamp)
+
+	return "SOCS=" + base64.RawURLEncoding.EncodeToString(b)
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+
+	return append(b, byte(v))
+}
+
+func appendProtoVarint(b []byte, field uint64, v uint64) []byte {
+	b = appendVarint(b, field<<3)
+
+	return appendVarint(b, v)
+}
+
+func appendProtoBytes(b []byte, field uint64, v []byte) []byte {
+	b = appendVarint(b, field<<3|2)
+	b = appendVarint(b, uint64(len(v)))
+
+	return append(b, v...)
+}
@@ -0,0 +1,383 @@
+package proxypool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	fhttp "github.com/bogdanfinn/fhttp"
+	tlsclient "github.com/bogdanfinn/tls-client"
+
+	youtube "github.com/rubpy/crawly-live-youtube"
+	"github.com/rubpy/crawly/cclient"
+	"github.com/rubpy/crawly/clog"
+)
+
+//////////////////////////////////////////////////
+
+// cclient.Client which spreads requests over a set of proxies (round-robin),
+// and takes proxies that get blocked (HTTP 429, captcha or consent redirects)
+// or fail too often out of rotation for a while.
+//
+// Meant to be given to the crawler via youtube.WithClient; only scraping
+// requests go through it (the Data API service has its own HTTP client).
+type Pool struct {
+	logger   atomic.Pointer[slog.Logger]
+	clock    youtube.Clock
+	settings Settings
+
+	proxies []*proxy
+	next    atomic.Uint64
+
+	// Given to every proxy's client (see SetDefaultHeader and SetCookieJar).
+	mu     sync.Mutex
+	header http.Header
+	jar    fhttp.CookieJar
+}
+
+var _ cclient.Client = (*Pool)(nil)
+
+type proxy struct {
+	name   string
+	client cclient.Client
+
+	mu       sync.Mutex
+	requests uint64
+	failures uint64
+	blocks   uint64
+
+	// Ring buffer of the outcomes (true if failed) of the most recent
+	// requests.
+	window    []bool
+	windowPos int
+	windowLen int
+
+	quarantines      int
+	quarantinedUntil time.Time
+
+	lastError string
+	lastBlock youtube.BlockReason
+}
+
+var NoAvailableProxy = errors.New("no available proxy (all are quarantined)")
+
+func NewPool(opts ...ConfigOption) (*Pool, error) {
+	var cfg config
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := validateConfig(&cfg); err != nil {
+		return nil, err
+	}
+
+	p, err := buildPoolFromConfig(&cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+//////////////////////////////////////////////////
+
+// NOTE: requests are spread over several clients, none of which stands for
+// the pool; always returns nil.
+func (p *Pool) HTTPClient() tlsclient.HttpClient {
+	return nil
+}
+
+// Returns (a copy of) the header last given to SetDefaultHeader.
+func (p *Pool) DefaultHeader() http.Header {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.header.Clone()
+}
+
+func (p *Pool) SetDefaultHeader(header http.Header) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.header = header.Clone()
+	for _, px := range p.proxies {
+		px.client.SetDefaultHeader(header.Clone())
+	}
+}
+
+func (p *Pool) Logger() *slog.Logger {
+	return p.logger.Load()
+}
+
+func (p *Pool) SetLogger(logger *slog.Logger) {
+	p.logger.Store(logger)
+}
+
+func (p *Pool) Log(ctx context.Context, params clog.Params) {
+	logger := p.logger.Load()
+	if logger == nil {
+		return
+	}
+
+	clog.WithParams(logger, ctx, params)
+}
+
+// Returns the cookie jar last given to SetCookieJar (nil if none).
+func (p *Pool) CookieJar() fhttp.CookieJar {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.jar
+}
+
+func (p *Pool) SetCookieJar(jar fhttp.CookieJar) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.jar = jar
+	for _, px := range p.proxies {
+		px.client.SetCookieJar(jar)
+	}
+}
+
+func (p *Pool) SetCookies(u *url.URL, cookies []*fhttp.Cookie) {
+	for _, px := range p.proxies {
+		px.client.SetCookies(u, cookies)
+	}
+}
+
+//////////////////////////////////////////////////
+
+// Sends the request through the next available proxy. Requests without a body
+// which fail or get blocked are retried through another proxy (up to
+// Settings.MaximumAttempts); otherwise, the last response (blocked or not) is
+// returned as is.
+func (p *Pool) Request(ctx context.Context, method string, rawURL string, body io.Reader, headers http.Header) (resp *fhttp.Response, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	} else if err = ctx.Err(); err != nil {
+		return
+	}
+
+	attempts := p.settings.MaximumAttempts
+	if body != nil {
+		attempts = 1
+	}
+
+	tried := map[*proxy]bool{}
+	for attempt := 1; attempt <= attempts; attempt++ {
+		px := p.pick(p.clock.Now(), tried)
+		if px == nil {
+			if resp == nil && err == nil {
+				err = NoAvailableProxy
+			}
+
+			return
+		}
+		tried[px] = true
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		resp, err = px.client.Request(ctx, method, rawURL, body, headers)
+		if err != nil && ctx.Err() != nil {
+			return
+		}
+
+		reason := youtube.DetectBlock(resp)
+		p.record(ctx, px, resp, err, reason)
+
+		if err == nil && reason == youtube.BlockNone {
+			return
+		}
+	}
+
+	return
+}
+
+// Returns the next proxy (round-robin) which is neither quarantined nor
+// excluded, or nil.
+func (p *Pool) pick(now time.Time, exclude map[*proxy]bool) *proxy {
+	n := uint64(len(p.proxies))
+	start := p.next.Add(1) - 1
+
+	for i := uint64(0); i < n; i++ {
+		px := p.proxies[(start+i)%n]
+		if exclude[px] || px.quarantined(now) {
+			continue
+		}
+
+		return px
+	}
+
+	return nil
+}
+
+func (p *Pool) record(ctx context.Context, px *proxy, resp *fhttp.Response, err error, reason youtube.BlockReason) {
+	now := p.clock.Now()
+	failed := err != nil || reason != youtube.BlockNone || (resp != nil && resp.StatusCode >= 500)
+
+	px.mu.Lock()
+	defer px.mu.Unlock()
+
+	px.requests++
+	if failed {
+		px.failures++
+
+		if err != nil {
+			px.lastError = err.Error()
+		} else if reason == youtube.BlockNone {
+			px.lastError = resp.Status
+		}
+	}
+	if reason != youtube.BlockNone {
+		px.blocks++
+		px.lastBlock = reason
+	}
+
+	px.window[px.windowPos] = failed
+	px.windowPos = (px.windowPos + 1) % len(px.window)
+	if px.windowLen < len(px.window) {
+		px.windowLen++
+	}
+
+	quarantine := reason != youtube.BlockNone
+	if !quarantine && px.windowLen == len(px.window) && px.errorRate() >= p.settings.MaximumErrorRate {
+		quarantine = true
+	}
+
+	if !quarantine {
+		if !failed {
+			px.quarantines = 0
+		}
+
+		return
+	}
+
+	d := p.settings.QuarantineDuration
+	for i := 0; i < px.quarantines && d < p.settings.MaximumQuarantineDuration; i++ {
+		d *= 2
+	}
+	d = min(d, p.settings.MaximumQuarantineDuration)
+
+	px.quarantines++
+	px.quarantinedUntil = now.Add(d)
+	px.windowPos, px.windowLen = 0, 0
+
+	p.Log(ctx, clog.Params{
+		Message: "quarantine",
+		Level:   slog.LevelWarn,
+
+		Values: clog.ParamGroup{
+			"proxy":    px.name,
+			"reason":   reason.String(),
+			"duration": d,
+		},
+		Err: err,
+	})
+}
+
+func (px *proxy) quarantined(now time.Time) bool {
+	px.mu.Lock()
+	defer px.mu.Unlock()
+
+	return now.Before(px.quarantinedUntil)
+}
+
+// NOTE: px.mu must be held.
+func (px *proxy) errorRate() float64 {
+	if px.windowLen == 0 {
+		return 0
+	}
+
+	failures := 0
+	for i := 0; i < px.windowLen; i++ {
+		if px.window[i] {
+			failures++
+		}
+	}
+
+	return float64(failures) / float64(px.windowLen)
+}
+
+//////////////////////////////////////////////////
+
+type ProxyHealth struct {
+	Name string `json:"name"`
+
+	Requests  uint64  `json:"requests"`
+	Failures  uint64  `json:"failures"`
+	Blocks    uint64  `json:"blocks"`
+	ErrorRate float64 `json:"error_rate"`
+
+	Quarantined      bool      `json:"quarantined"`
+	QuarantinedUntil time.Time `json:"quarantined_until"`
+	Quarantines      int       `json:"quarantines"`
+
+	LastError string              `json:"last_error"`
+	LastBlock youtube.BlockReason `json:"last_block"`
+}
+
+type Health struct {
+	Proxies   []ProxyHealth `json:"proxies"`
+	Available int           `json:"available"`
+}
+
+// Returns a snapshot of every proxy's statistics.
+func (p *Pool) Health() (health Health) {
+	now := p.clock.Now()
+
+	for _, px := range p.proxies {
+		px.mu.Lock()
+		ph := ProxyHealth{
+			Name: px.name,
+
+			Requests:  px.requests,
+			Failures:  px.failures,
+			Blocks:    px.blocks,
+			ErrorRate: px.errorRate(),
+
+			Quarantined:      now.Before(px.quarantinedUntil),
+			QuarantinedUntil: px.quarantinedUntil,
+			Quarantines:      px.quarantines,
+
+			LastError: px.lastError,
+			LastBlock: px.lastBlock,
+		}
+		px.mu.Unlock()
+
+		if !ph.Quarantined {
+			health.Available++
+		}
+		health.Proxies = append(health.Proxies, ph)
+	}
+
+	return
+}
+
+// Lifts the quarantine of the named proxy; returns false if there is no such
+// proxy.
+func (p *Pool) Release(name string) bool {
+	for _, px := range p.proxies {
+		if px.name != name {
+			continue
+		}
+
+		px.mu.Lock()
+		px.quarantinedUntil = time.Time{}
+		px.quarantines = 0
+		px.mu.Unlock()
+
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,182 @@
+package proxypool_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rubpy/crawly-live-youtube/proxypool"
+	"github.com/rubpy/crawly-live-youtube/youtubetest"
+)
+
+const (
+	testChannelID = "UCSJ4gkVC6NrvII8umztf0Ow"
+	testFeedURL   = "https://www.youtube.com/feeds/videos.xml?channel_id=" + testChannelID
+)
+
+// Returns a fake server standing for a single proxy.
+func newTestServer(t *testing.T) *youtubetest.Server {
+	t.Helper()
+
+	srv := youtubetest.NewServer()
+	t.Cleanup(srv.Close)
+
+	if err := srv.AddChannel(youtubetest.Channel{ID: testChannelID, Title: "Test"}); err != nil {
+		t.Fatalf("AddChannel: %v", err)
+	}
+
+	return srv
+}
+
+func rateLimited(srv *youtubetest.Server) {
+	srv.Handle("/feeds/videos.xml", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+}
+
+func request(t *testing.T, p *proxypool.Pool) (status int, err error) {
+	t.Helper()
+
+	resp, err := p.Request(context.Background(), http.MethodGet, testFeedURL, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+func proxyHealth(t *testing.T, health proxypool.Health, name string) proxypool.ProxyHealth {
+	t.Helper()
+
+	for _, ph := range health.Proxies {
+		if ph.Name == name {
+			return ph
+		}
+	}
+
+	t.Fatalf("no health reported for proxy %q", name)
+	return proxypool.ProxyHealth{}
+}
+
+func TestPoolRotation(t *testing.T) {
+	a, b, c := newTestServer(t), newTestServer(t), newTestServer(t)
+	rateLimited(a)
+
+	clk := youtubetest.NewClock(time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC))
+	p, err := proxypool.NewPool(
+		proxypool.WithClock(clk),
+		proxypool.WithClient("a", a.Client()),
+		proxypool.WithClient("b", b.Client()),
+		proxypool.WithClient("c", c.Client()),
+	)
+	if err != nil {
+		t.Fatalf("NewPool: %v", err)
+	}
+
+	// NOTE: the first request is blocked on "a" (quarantining it), and retried
+	// on "b"; "a" is skipped from then on.
+	for i := 0; i < 3; i++ {
+		if status, err := request(t, p); err != nil || status != http.StatusOK {
+			t.Fatalf("request %d: got %d, %v", i, status, err)
+		}
+	}
+
+	health := p.Health()
+	if health.Available != 2 {
+		t.Fatalf("got %d available proxies, want 2", health.Available)
+	}
+
+	want := map[string]uint64{"a": 1, "b": 2, "c": 1}
+	for name, requests := range want {
+		if ph := proxyHealth(t, health, name); ph.Requests != requests {
+			t.Errorf("%s: got %d requests, want %d", name, ph.Requests, requests)
+		}
+	}
+
+	if ph := proxyHealth(t, health, "a"); !ph.Quarantined || ph.Blocks != 1 || ph.Failures != 1 || ph.LastBlock.String() == "" {
+		t.Errorf("a: got %+v", ph)
+	}
+}
+
+func TestPoolDefaultHeader(t *testing.T) {
+	a, b := newTestServer(t).Client(), newTestServer(t).Client()
+
+	p, err := proxypool.NewPool(
+		proxypool.WithClient("a", a),
+		proxypool.WithClient("b", b),
+	)
+	if err != nil {
+		t.Fatalf("NewPool: %v", err)
+	}
+
+	p.SetDefaultHeader(http.Header{"X-Test": {"1"}})
+	p.DefaultHeader().Set("X-Test", "2")
+
+	for name, h := range map[string]http.Header{"pool": p.DefaultHeader(), "a": a.DefaultHeader(), "b": b.DefaultHeader()} {
+		if got := h.Get("X-Test"); got != "1" {
+			t.Errorf("%s: got X-Test %q, want 1", name, got)
+		}
+	}
+}
+
+func TestPoolQuarantine(t *testing.T) {
+	srv := newTestServer(t)
+	rateLimited(srv)
+
+	settings := proxypool.DefaultSettings
+	settings.MaximumAttempts = 1
+
+	clk := youtubetest.NewClock(time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC))
+	p, err := proxypool.NewPool(
+		proxypool.WithClock(clk),
+		proxypool.WithSettings(settings),
+		proxypool.WithClient("a", srv.Client()),
+	)
+	if err != nil {
+		t.Fatalf("NewPool: %v", err)
+	}
+
+	// Every consecutive quarantine lasts twice as long as the previous one.
+	for i, d := range []time.Duration{settings.QuarantineDuration, 2 * settings.QuarantineDuration, 4 * settings.QuarantineDuration} {
+		if status, err := request(t, p); err != nil || status != http.StatusTooManyRequests {
+			t.Fatalf("quarantine %d: got %d, %v", i, status, err)
+		}
+
+		ph := proxyHealth(t, p.Health(), "a")
+		if ph.Quarantines != i+1 || !ph.QuarantinedUntil.Equal(clk.Now().Add(d)) {
+			t.Fatalf("quarantine %d: got %d quarantines until %s, want until %s", i, ph.Quarantines, ph.QuarantinedUntil, clk.Now().Add(d))
+		}
+
+		if _, err := request(t, p); !errors.Is(err, proxypool.NoAvailableProxy) {
+			t.Fatalf("quarantine %d: got %v, want NoAvailableProxy", i, err)
+		}
+
+		clk.Advance(d)
+	}
+
+	if _, err := request(t, p); err != nil {
+		t.Fatalf("after the last quarantine: %v", err)
+	}
+	if !p.Release("a") || p.Release("b") {
+		t.Fatalf("unexpected result from Release")
+	}
+
+	ph := proxyHealth(t, p.Health(), "a")
+	if ph.Quarantined || ph.Quarantines != 0 {
+		t.Fatalf("got %+v after Release", ph)
+	}
+
+	// Released (and no longer blocked), the proxy starts over.
+	srv.Handle("/feeds/videos.xml", nil)
+	if status, err := request(t, p); err != nil || status != http.StatusOK {
+		t.Fatalf("after Release: got %d, %v", status, err)
+	}
+
+	health := p.Health()
+	if ph := proxyHealth(t, health, "a"); health.Available != 1 || ph.Requests != 5 || ph.Blocks != 4 {
+		t.Fatalf("got %+v", health)
+	}
+}
@@ -0,0 +1,95 @@
+package youtube
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+//////////////////////////////////////////////////
+
+// Quota cost (in units) of the Data API's list methods (videos.list,
+// channels.list, playlistItems.list).
+const quotaCostList = 1
+
+var QuotaExceeded = errors.New("daily Data API quota budget exceeded")
+
+// Data API quota used by the crawler during the current quota day (which, as
+// for the Data API itself, starts at midnight Pacific Time).
+type QuotaUsage struct {
+	Day   string           `json:"day"` // (e.g., "2024-05-01")
+	Units int64            `json:"units"`
+	Calls map[string]int64 `json:"calls"` // (per method, e.g., "playlistItems.list")
+}
+
+type quotaTracker struct {
+	mu    sync.Mutex
+	usage QuotaUsage
+}
+
+var quotaLocation = func() *time.Location {
+	if loc, err := time.LoadLocation("America/Los_Angeles"); err == nil {
+		return loc
+	}
+
+	return time.FixedZone("PST", -8*60*60)
+}()
+
+func quotaDay(now time.Time) string {
+	return now.In(quotaLocation).Format(time.DateOnly)
+}
+
+// Must be called with q.mu held.
+func (q *quotaTracker) rollover(now time.Time) {
+	if day := quotaDay(now); q.usage.Day != day {
+		q.usage = QuotaUsage{
+			Day:   day,
+			Calls: map[string]int64{},
+		}
+	}
+}
+
+//////////////////////////////////////////////////
+
+// Returns the Data API quota used by the crawler so far today.
+func (cr *Crawler) QuotaUsage() (usage QuotaUsage) {
+	q := &cr.quota
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover(cr.now())
+
+	usage = q.usage
+	usage.Calls = make(map[string]int64, len(q.usage.Calls))
+	for method, calls := range q.usage.Calls {
+		usage.Calls[method] = calls
+	}
+
+	return
+}
+
+// Accounts for a Data API call.
+func (cr *Crawler) recordQuota(method string, units int64) {
+	q := &cr.quota
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover(cr.now())
+
+	q.usage.Units += units
+	q.usage.Calls[method]++
+}
+
+// Checks whether a (non-essential) Data API call of the given cost still fits
+// within DailyQuotaBudget.
+func (cr *Crawler) checkQuota(units int64, settings CrawlerSettings) error {
+	if settings.DailyQuotaBudget <= 0 {
+		return nil
+	}
+
+	if cr.QuotaUsage().Units+units > settings.DailyQuotaBudget {
+		return QuotaExceeded
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package youtube
+
+import (
+	"time"
+)
+
+//////////////////////////////////////////////////
+
+const hoursPerWeek = 7 * 24
+
+// Maximum number of videos remembered by StreamSchedule (to avoid counting
+// the same livestream more than once).
+var maximumScheduleObservedVideos = 64
+
+// Histogram of the times (hour-of-week, in UTC) at which a channel's
+// livestreams have been observed to start.
+type StreamSchedule struct {
+	Histogram    [hoursPerWeek]uint16 `json:"histogram"`
+	Observations int                  `json:"observations"`
+
+	ObservedVideos []ObservedVideo `json:"observed_videos"`
+	// Latest start time of the videos no longer remembered; livestreams that
+	// started no later than that are assumed to have been observed already.
+	ObservedBefore time.Time `json:"observed_before"`
+}
+
+type ObservedVideo struct {
+	ID    string    `json:"id"`
+	Start time.Time `json:"start"`
+}
+
+func hourOfWeek(t time.Time) int {
+	t = t.UTC()
+
+	return int(t.Weekday())*24 + t.Hour()
+}
+
+// Records the actual start time of a livestream (once per video); returns
+// false if the video has already been observed.
+func (s *StreamSchedule) Observe(videoID string, start time.Time) bool {
+	if start.IsZero() || !start.After(s.ObservedBefore) {
+		return false
+	}
+
+	for _, ov := range s.ObservedVideos {
+		if ov.ID == videoID {
+			return false
+		}
+	}
+
+	s.ObservedVideos = append(s.ObservedVideos, ObservedVideo{ID: videoID, Start: start})
+	if len(s.ObservedVideos) > maximumScheduleObservedVideos {
+		// NOTE: forgets the video that started first (rather than the one
+		// observed first), so that ObservedBefore covers every forgotten one.
+		oldest := 0
+		for i, ov := range s.ObservedVideos {
+			if ov.Start.Before(s.ObservedVideos[oldest].Start) {
+				oldest = i
+			}
+		}
+
+		s.ObservedBefore = s.ObservedVideos[oldest].Start
+		s.ObservedVideos = append(s.ObservedVideos[:oldest:oldest], s.ObservedVideos[oldest+1:]...)
+	}
+
+	h := hourOfWeek(start)
+	if s.Histogram[h] < ^uint16(0) {
+		s.Histogram[h]++
+	}
+	s.Observations++
+
+	return true
+}
+
+// Returns the number of observed go-live times that fall within window of
+// t's hour-of-week, along with the number of hour buckets considered.
+func (s *StreamSchedule) near(t time.Time, window time.Duration) (count int, buckets int) {
+	seen := [hoursPerWeek]bool{}
+
+	for offset := -window; offset <= window; offset += time.Hour {
+		h := hourOfWeek(t.Add(offset))
+		if seen[h] {
+			continue
+		}
+		seen[h] = true
+
+		count += int(s.Histogram[h])
+		buckets++
+	}
+
+	return
+}
+
+//////////////////////////////////////////////////
+
+type scheduleActivity uint
+
+const (
+	scheduleUnknown scheduleActivity = iota
+	scheduleLikely
+	scheduleQuiet
+)
+
+// Classifies t as either a likely go-live window (the share of observations
+// near t is at least what a uniform schedule would give), or a quiet one (no
+// observations near t at all).
+func (s *StreamSchedule) activity(t time.Time, window time.Duration, minimumObservations int) scheduleActivity {
+	if s.Observations == 0 || s.Observations < minimumObservations {
+		return scheduleUnknown
+	}
+
+	count, buckets := s.near(t, window)
+	if count == 0 {
+		return scheduleQuiet
+	}
+
+	if float64(count)/float64(s.Observations) >= float64(buckets)/hoursPerWeek {
+		return scheduleLikely
+	}
+
+	return scheduleUnknown
+}
+
+// Returns the feed fetch and video check delays adjusted to the channel's
+// streaming schedule (or the unchanged settings, if adaptive polling is
+// disabled or not enough is known about the schedule yet).
+//
+// NOTE: delays are never backed off while the channel is live.
+func (settings CrawlerSettings) adaptiveDelays(schedule *StreamSchedule, now time.Time, live bool) (fetchChannelFeedDelay time.Duration, checkVideoDelay time.Duration) {
+	fetchChannelFeedDelay = settings.MinimumFetchChannelFeedDelay
+	checkVideoDelay = settings.MinimumCheckVideoDelay
+
+	if !settings.AdaptivePolling || schedule == nil {
+		return
+	}
+
+	switch schedule.activity(now, settings.AdaptiveWindow, settings.AdaptiveMinimumObservations) {
+	case scheduleLikely:
+		fetchChannelFeedDelay = min(fetchChannelFeedDelay, settings.AdaptiveMinimumFetchChannelFeedDelay)
+		checkVideoDelay = min(checkVideoDelay, settings.AdaptiveMinimumCheckVideoDelay)
+
+	case scheduleQuiet:
+		if !live {
+			fetchChannelFeedDelay = max(fetchChannelFeedDelay, settings.AdaptiveMaximumFetchChannelFeedDelay)
+			checkVideoDelay = max(checkVideoDelay, settings.AdaptiveMaximumCheckVideoDelay)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,36 @@
+package youtube
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStreamScheduleObserve(t *testing.T) {
+	defer func(n int) { maximumScheduleObservedVideos = n }(maximumScheduleObservedVideos)
+	maximumScheduleObservedVideos = 2
+
+	start := time.Date(2026, 1, 5, 18, 0, 0, 0, time.UTC)
+	id := func(i int) string { return fmt.Sprintf("video%06d", i) }
+
+	var s StreamSchedule
+	for i := 0; i < 4; i++ {
+		if !s.Observe(id(i), start.Add(time.Duration(i)*24*time.Hour)) {
+			t.Fatalf("Observe(%s) = false, want true", id(i))
+		}
+	}
+
+	// NOTE: videos 0 and 1 are no longer remembered by ID.
+	for i := 0; i < 4; i++ {
+		if s.Observe(id(i), start.Add(time.Duration(i)*24*time.Hour)) {
+			t.Fatalf("Observe(%s) = true after it was observed", id(i))
+		}
+	}
+
+	if s.Observations != 4 || len(s.ObservedVideos) != 2 {
+		t.Fatalf("got %d observations (%d remembered), want 4 (2)", s.Observations, len(s.ObservedVideos))
+	}
+	if !s.Observe(id(4), start.Add(4*24*time.Hour)) {
+		t.Fatalf("Observe(%s) = false, want true", id(4))
+	}
+}
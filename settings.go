@@ -1,9 +1,13 @@
 package youtube
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/rubpy/crawly"
+	"github.com/rubpy/crawly-live-youtube/xmlapi"
 )
 
 //////////////////////////////////////////////////
@@ -14,15 +18,86 @@ type CrawlerSettings struct {
 	// Stop looking for more live videos after this number is reached (e.g.,
 	// if this is set to 1, crawler will not scan remaining videos once it has
 	// found one livestream).
-	StopAfterLiveVideos int
+	StopAfterLiveVideos int `json:"stop_after_live_videos"`
 
-	MinimumFetchChannelFeedDelay       time.Duration
-	MaximumCachedNotLivestreamAge      time.Duration
-	MaximumCachedLivestreamFinishedAge time.Duration
-	MinimumCheckVideoDelay             time.Duration
-	MaximumVideoAge                    time.Duration
+	MinimumFetchChannelFeedDelay       time.Duration `json:"minimum_fetch_channel_feed_delay"`
+	MaximumCachedNotLivestreamAge      time.Duration `json:"maximum_cached_not_livestream_age"`
+	MaximumCachedLivestreamFinishedAge time.Duration `json:"maximum_cached_livestream_finished_age"`
+	MinimumCheckVideoDelay             time.Duration `json:"minimum_check_video_delay"`
+	MaximumVideoAge                    time.Duration `json:"maximum_video_age"`
 
-	CheckVideoTimeout time.Duration
+	CheckVideoTimeout time.Duration `json:"check_video_timeout"`
+
+	// Adjust MinimumFetchChannelFeedDelay and MinimumCheckVideoDelay to each
+	// channel's streaming schedule (learnt from the start times of its past
+	// livestreams): polling is sped up (down to the Adaptive*Minimum* bounds)
+	// within AdaptiveWindow of times the channel usually goes live at, and
+	// slowed down (up to the Adaptive*Maximum* bounds) during hours in which
+	// it has never gone live. Channels are also refetched periodically (at
+	// the adjusted feed fetch delay) rather than only once none of their video
+	// candidates remain.
+	AdaptivePolling                      bool          `json:"adaptive_polling"`
+	AdaptiveMinimumObservations          int           `json:"adaptive_minimum_observations"`
+	AdaptiveWindow                       time.Duration `json:"adaptive_window"`
+	AdaptiveMinimumFetchChannelFeedDelay time.Duration `json:"adaptive_minimum_fetch_channel_feed_delay"`
+	AdaptiveMaximumFetchChannelFeedDelay time.Duration `json:"adaptive_maximum_fetch_channel_feed_delay"`
+	AdaptiveMinimumCheckVideoDelay       time.Duration `json:"adaptive_minimum_check_video_delay"`
+	AdaptiveMaximumCheckVideoDelay       time.Duration `json:"adaptive_maximum_check_video_delay"`
+
+	// Stop making requests to a (scraping) endpoint for CircuitBreakerCooldown
+	// after it has been blocked (rate limited, captcha or consent wall)
+	// CircuitBreakerThreshold times in a row; a threshold of 0 disables the
+	// circuit breaker.
+	CircuitBreakerThreshold int           `json:"circuit_breaker_threshold"`
+	CircuitBreakerCooldown  time.Duration `json:"circuit_breaker_cooldown"`
+
+	// What to do with a channel once it is found to be gone (terminated or
+	// deleted); see GonePolicy.
+	GonePolicy                GonePolicy    `json:"gone_policy"`
+	GoneFetchChannelFeedDelay time.Duration `json:"gone_fetch_channel_feed_delay"`
+
+	// Failed entities and video candidates are retried after
+	// FailureBackoffBase, doubled with every consecutive failure (up to
+	// FailureBackoffMaximum), and shortened by up to a FailureBackoffJitter
+	// fraction (0-1); after MaximumFailures consecutive failures, they are
+	// parked until ResetFailures is called (0 means never).
+	FailureBackoffBase    time.Duration `json:"failure_backoff_base"`
+	FailureBackoffMaximum time.Duration `json:"failure_backoff_maximum"`
+	FailureBackoffJitter  float64       `json:"failure_backoff_jitter"`
+	MaximumFailures       int           `json:"maximum_failures"`
+
+	// Maximum size (in bytes) of a response body read from a scraping
+	// endpoint; larger feeds and channel pages are rejected (with an error
+	// wrapping xmlapi.BodyTooLarge). 0 means no limit.
+	MaximumResponseBodySize int64 `json:"maximum_response_body_size"`
+
+	// Refresh the cached metadata of tracked channels (see
+	// Crawler.ChannelInfo) once it is older than this; 0 means never (the
+	// metadata is then only cached when resolving channel URLs).
+	MaximumCachedChannelInfoAge time.Duration `json:"maximum_cached_channel_info_age"`
+
+	// Sources from which video candidates are gathered (see
+	// CandidateSources); with SourceUploads, up to UploadsDepth of the
+	// channel's latest uploads are listed.
+	CandidateSources CandidateSources `json:"candidate_sources"`
+	UploadsDepth     int              `json:"uploads_depth"`
+
+	// Daily budget (in units) of Data API quota; once it has been used up,
+	// SourceUploads is skipped (falling back to the other sources) until the
+	// quota resets. 0 means no budget (see Crawler.QuotaUsage).
+	DailyQuotaBudget int64 `json:"daily_quota_budget"`
+
+	// Leave premieres out of EntityData.Live (and LiveVideos), reporting
+	// only genuine livestreams.
+	ExcludePremieres bool `json:"exclude_premieres"`
+
+	// Rules deciding which live videos count towards EntityData.Live (see
+	// StreamFilter); nil means all of them. Typically set per handle.
+	Filter *StreamFilter `json:"filter,omitempty"`
+
+	// Named settings overrides (e.g., "fast", "archive"), which handles can
+	// refer to via SettingsOverride.Tier.
+	Tiers map[string]SettingsOverride `json:"tiers,omitempty"`
 }
 
 var DefaultSettings = CrawlerSettings{
@@ -37,6 +112,229 @@ var DefaultSettings = CrawlerSettings{
 	MaximumVideoAge:                    60 * 24 * time.Hour,
 
 	CheckVideoTimeout: 10 * time.Second,
+
+	AdaptivePolling:                      false,
+	AdaptiveMinimumObservations:          5,
+	AdaptiveWindow:                       1 * time.Hour,
+	AdaptiveMinimumFetchChannelFeedDelay: 15 * time.Second,
+	AdaptiveMaximumFetchChannelFeedDelay: 5 * time.Minute,
+	AdaptiveMinimumCheckVideoDelay:       10 * time.Second,
+	AdaptiveMaximumCheckVideoDelay:       5 * time.Minute,
+
+	CircuitBreakerThreshold: 3,
+	CircuitBreakerCooldown:  5 * time.Minute,
+
+	GonePolicy:                GonePolicyDowngrade,
+	GoneFetchChannelFeedDelay: 6 * time.Hour,
+
+	FailureBackoffBase:    30 * time.Second,
+	FailureBackoffMaximum: 30 * time.Minute,
+	FailureBackoffJitter:  0.2,
+	MaximumFailures:       0,
+
+	MaximumResponseBodySize: xmlapi.DefaultMaximumBodySize,
+
+	MaximumCachedChannelInfoAge: 24 * time.Hour,
+
+	CandidateSources: SourceFeed,
+	UploadsDepth:     50,
+	DailyQuotaBudget: 0,
+
+	ExcludePremieres: false,
+}
+
+//////////////////////////////////////////////////
+
+// Lower bound of the delays and cache ages (a zero value is raised to it by
+// Normalized; anything between zero and this value is rejected by Validate).
+var MinimumSettingsDelay = 1 * time.Second
+
+var InvalidSettings = errors.New("invalid settings")
+
+// Describes a single invalid field of CrawlerSettings.
+type SettingsError struct {
+	Field  string
+	Value  any
+	Reason string
+}
+
+func (e *SettingsError) Error() string {
+	return fmt.Sprintf("%s: %s (got %v)", e.Field, e.Reason, e.Value)
+}
+
+func (e *SettingsError) Unwrap() error {
+	return InvalidSettings
+}
+
+type SettingsErrors []*SettingsError
+
+func (errs SettingsErrors) Error() string {
+	var s strings.Builder
+
+	s.WriteString("invalid settings: ")
+	for i, e := range errs {
+		if i > 0 {
+			s.WriteString("; ")
+		}
+
+		s.WriteString(e.Error())
+	}
+
+	return s.String()
+}
+
+func (errs SettingsErrors) Unwrap() []error {
+	u := make([]error, len(errs))
+	for i, e := range errs {
+		u[i] = e
+	}
+
+	return u
+}
+
+// Checks settings for values that make no sense (e.g., negative durations),
+// and returns SettingsErrors listing every offending field.
+func (settings CrawlerSettings) Validate() error {
+	var errs SettingsErrors
+
+	nonNegative := func(field string, v time.Duration) {
+		if v < 0 {
+			errs = append(errs, &SettingsError{field, v, "must not be negative"})
+		}
+	}
+	delay := func(field string, v time.Duration) {
+		if v < 0 || (v > 0 && v < MinimumSettingsDelay) {
+			errs = append(errs, &SettingsError{field, v, fmt.Sprintf("must be either 0 or at least %s", MinimumSettingsDelay)})
+		}
+	}
+
+	nonNegative("TrackingOrderTimeout", settings.TrackingOrderTimeout)
+	nonNegative("MinimumTrackingOrderDelay", settings.MinimumTrackingOrderDelay)
+	nonNegative("TrackingTimeout", settings.TrackingTimeout)
+	nonNegative("MinimumTrackingDelay", settings.MinimumTrackingDelay)
+
+	if settings.StopAfterLiveVideos < 0 {
+		errs = append(errs, &SettingsError{"StopAfterLiveVideos", settings.StopAfterLiveVideos, "must not be negative"})
+	}
+
+	delay("MinimumFetchChannelFeedDelay", settings.MinimumFetchChannelFeedDelay)
+	delay("MaximumCachedNotLivestreamAge", settings.MaximumCachedNotLivestreamAge)
+	delay("MaximumCachedLivestreamFinishedAge", settings.MaximumCachedLivestreamFinishedAge)
+	delay("MinimumCheckVideoDelay", settings.MinimumCheckVideoDelay)
+
+	if settings.MaximumVideoAge <= 0 {
+		errs = append(errs, &SettingsError{"MaximumVideoAge", settings.MaximumVideoAge, "must be positive"})
+	}
+	nonNegative("CheckVideoTimeout", settings.CheckVideoTimeout)
+
+	if settings.AdaptiveMinimumObservations < 0 {
+		errs = append(errs, &SettingsError{"AdaptiveMinimumObservations", settings.AdaptiveMinimumObservations, "must not be negative"})
+	}
+	nonNegative("AdaptiveWindow", settings.AdaptiveWindow)
+	delay("AdaptiveMinimumFetchChannelFeedDelay", settings.AdaptiveMinimumFetchChannelFeedDelay)
+	delay("AdaptiveMaximumFetchChannelFeedDelay", settings.AdaptiveMaximumFetchChannelFeedDelay)
+	delay("AdaptiveMinimumCheckVideoDelay", settings.AdaptiveMinimumCheckVideoDelay)
+	delay("AdaptiveMaximumCheckVideoDelay", settings.AdaptiveMaximumCheckVideoDelay)
+
+	if settings.AdaptivePolling {
+		if settings.AdaptiveMinimumFetchChannelFeedDelay > settings.AdaptiveMaximumFetchChannelFeedDelay {
+			errs = append(errs, &SettingsError{"AdaptiveMinimumFetchChannelFeedDelay", settings.AdaptiveMinimumFetchChannelFeedDelay, "must not exceed AdaptiveMaximumFetchChannelFeedDelay"})
+		}
+		if settings.AdaptiveMinimumCheckVideoDelay > settings.AdaptiveMaximumCheckVideoDelay {
+			errs = append(errs, &SettingsError{"AdaptiveMinimumCheckVideoDelay", settings.AdaptiveMinimumCheckVideoDelay, "must not exceed AdaptiveMaximumCheckVideoDelay"})
+		}
+	}
+
+	if settings.CircuitBreakerThreshold < 0 {
+		errs = append(errs, &SettingsError{"CircuitBreakerThreshold", settings.CircuitBreakerThreshold, "must not be negative"})
+	}
+	delay("CircuitBreakerCooldown", settings.CircuitBreakerCooldown)
+
+	if _, ok := gonePolicyNames[settings.GonePolicy]; !ok {
+		errs = append(errs, &SettingsError{"GonePolicy", settings.GonePolicy, "must be one of keep, untrack or downgrade"})
+	}
+	delay("GoneFetchChannelFeedDelay", settings.GoneFetchChannelFeedDelay)
+
+	delay("FailureBackoffBase", settings.FailureBackoffBase)
+	delay("FailureBackoffMaximum", settings.FailureBackoffMaximum)
+	if settings.FailureBackoffBase > settings.FailureBackoffMaximum {
+		errs = append(errs, &SettingsError{"FailureBackoffBase", settings.FailureBackoffBase, "must not exceed FailureBackoffMaximum"})
+	}
+	if settings.FailureBackoffJitter < 0 || settings.FailureBackoffJitter > 1 {
+		errs = append(errs, &SettingsError{"FailureBackoffJitter", settings.FailureBackoffJitter, "must be between 0 and 1"})
+	}
+	if settings.MaximumFailures < 0 {
+		errs = append(errs, &SettingsError{"MaximumFailures", settings.MaximumFailures, "must not be negative"})
+	}
+
+	delay("MaximumCachedChannelInfoAge", settings.MaximumCachedChannelInfoAge)
+
+	if !settings.CandidateSources.valid() {
+		errs = append(errs, &SettingsError{"CandidateSources", settings.CandidateSources, "must be a non-empty combination of feed, uploads and streams"})
+	}
+	if settings.UploadsDepth < 0 {
+		errs = append(errs, &SettingsError{"UploadsDepth", settings.UploadsDepth, "must not be negative"})
+	}
+	if settings.DailyQuotaBudget < 0 {
+		errs = append(errs, &SettingsError{"DailyQuotaBudget", settings.DailyQuotaBudget, "must not be negative"})
+	}
+
+	if settings.MaximumResponseBodySize < 0 {
+		errs = append(errs, &SettingsError{"MaximumResponseBodySize", settings.MaximumResponseBodySize, "must not be negative"})
+	}
+
+	errs = append(errs, settings.Filter.validate()...)
+
+	errs = append(errs, settings.validateTiers()...)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// Returns the settings as effectively used by the crawler: delays and cache
+// ages are raised to at least MinimumSettingsDelay, and a StopAfterLiveVideos
+// of 0 means "no limit".
+func (settings CrawlerSettings) Normalized() CrawlerSettings {
+	atLeast := func(min time.Duration, v time.Duration) time.Duration {
+		if v < min {
+			return min
+		}
+
+		return v
+	}
+
+	if settings.StopAfterLiveVideos < 0 {
+		settings.StopAfterLiveVideos = 0
+	}
+
+	settings.MinimumFetchChannelFeedDelay = atLeast(MinimumSettingsDelay, settings.MinimumFetchChannelFeedDelay)
+	settings.MaximumCachedNotLivestreamAge = atLeast(MinimumSettingsDelay, settings.MaximumCachedNotLivestreamAge)
+	settings.MaximumCachedLivestreamFinishedAge = atLeast(MinimumSettingsDelay, settings.MaximumCachedLivestreamFinishedAge)
+	settings.MinimumCheckVideoDelay = atLeast(MinimumSettingsDelay, settings.MinimumCheckVideoDelay)
+	settings.MaximumVideoAge = atLeast(0, settings.MaximumVideoAge)
+	settings.CheckVideoTimeout = atLeast(0, settings.CheckVideoTimeout)
+
+	settings.AdaptiveWindow = atLeast(0, settings.AdaptiveWindow)
+	settings.AdaptiveMinimumFetchChannelFeedDelay = atLeast(MinimumSettingsDelay, settings.AdaptiveMinimumFetchChannelFeedDelay)
+	settings.AdaptiveMaximumFetchChannelFeedDelay = atLeast(MinimumSettingsDelay, settings.AdaptiveMaximumFetchChannelFeedDelay)
+	settings.AdaptiveMinimumCheckVideoDelay = atLeast(MinimumSettingsDelay, settings.AdaptiveMinimumCheckVideoDelay)
+	settings.AdaptiveMaximumCheckVideoDelay = atLeast(MinimumSettingsDelay, settings.AdaptiveMaximumCheckVideoDelay)
+
+	if settings.CircuitBreakerThreshold < 0 {
+		settings.CircuitBreakerThreshold = 0
+	}
+	settings.CircuitBreakerCooldown = atLeast(MinimumSettingsDelay, settings.CircuitBreakerCooldown)
+	settings.GoneFetchChannelFeedDelay = atLeast(MinimumSettingsDelay, settings.GoneFetchChannelFeedDelay)
+
+	settings.FailureBackoffBase = atLeast(0, settings.FailureBackoffBase)
+	settings.FailureBackoffMaximum = atLeast(settings.FailureBackoffBase, settings.FailureBackoffMaximum)
+	settings.FailureBackoffJitter = min(max(settings.FailureBackoffJitter, 0), 1)
+	settings.MaximumFailures = max(settings.MaximumFailures, 0)
+
+	return settings
 }
 
 //////////////////////////////////////////////////
@@ -45,8 +343,14 @@ func (cr *Crawler) loadSettings() CrawlerSettings {
 	return cr.settings.Load()
 }
 
+func (cr *Crawler) loadEffectiveSettings() CrawlerSettings {
+	return cr.effectiveSettings.Load()
+}
+
 func (cr *Crawler) setSettings(settings CrawlerSettings) {
 	cr.settings.Store(settings)
+	cr.effectiveSettings.Store(settings.Normalized())
+	cr.refreshHandleSettings(settings)
 	crawly.SetCrawlerSettings(&cr.Crawler, settings.CrawlerSettings)
 }
 
@@ -54,6 +358,23 @@ func (cr *Crawler) Settings() CrawlerSettings {
 	return cr.loadSettings()
 }
 
-func (cr *Crawler) SetSettings(settings CrawlerSettings) {
+// Returns the settings currently in use (see CrawlerSettings.Normalized).
+func (cr *Crawler) EffectiveSettings() CrawlerSettings {
+	return cr.loadEffectiveSettings()
+}
+
+func (cr *Crawler) SetSettings(settings CrawlerSettings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	cr.settingsMu.Lock()
+	defer cr.settingsMu.Unlock()
+
+	if err := cr.validateTierReferences(settings); err != nil {
+		return err
+	}
+
 	cr.setSettings(settings)
+	return nil
 }
@@ -0,0 +1,93 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//////////////////////////////////////////////////
+
+// Set of sources from which the video candidates of a channel are gathered
+// (and merged, without duplicates).
+type CandidateSources uint
+
+const (
+	// The channel's RSS feed (feeds/videos.xml), which lists its latest 15
+	// uploads.
+	SourceFeed CandidateSources = 1 << iota
+	// The channel's uploads playlist, via the Data API (costs quota; listed
+	// up to UploadsDepth videos deep).
+	SourceUploads
+	// The live and upcoming streams listed on the channel's "Live" tab
+	// (/channel/<id>/streams), which may not (yet) be in its feed.
+	SourceStreams
+)
+
+var candidateSourceNames = []struct {
+	source CandidateSources
+	name   string
+}{
+	{SourceFeed, "feed"},
+	{SourceUploads, "uploads"},
+	{SourceStreams, "streams"},
+}
+
+var InvalidCandidateSources = errors.New("invalid candidate sources")
+
+func (s CandidateSources) Has(source CandidateSources) bool {
+	return s&source != 0
+}
+
+func (s CandidateSources) valid() bool {
+	var all CandidateSources
+	for _, n := range candidateSourceNames {
+		all |= n.source
+	}
+
+	return s != 0 && s&^all == 0
+}
+
+// Returns the names of the sources, joined with commas (e.g., "feed,uploads").
+func (s CandidateSources) String() string {
+	names := make([]string, 0, len(candidateSourceNames))
+	for _, n := range candidateSourceNames {
+		if s.Has(n.source) {
+			names = append(names, n.name)
+		}
+	}
+
+	return strings.Join(names, ",")
+}
+
+func (s CandidateSources) MarshalText() ([]byte, error) {
+	if !s.valid() {
+		return nil, InvalidCandidateSources
+	}
+
+	return []byte(s.String()), nil
+}
+
+func (s *CandidateSources) UnmarshalText(b []byte) error {
+	var sources CandidateSources
+
+	for _, name := range strings.Split(string(b), ",") {
+		name = strings.TrimSpace(name)
+
+		found := false
+		for _, n := range candidateSourceNames {
+			if n.name == name {
+				sources |= n.source
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("%w: %q", InvalidCandidateSources, name)
+		}
+	}
+
+	*s = sources
+	return nil
+}
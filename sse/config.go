@@ -0,0 +1,107 @@
+package sse
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	youtube "github.com/rubpy/crawly-live-youtube"
+)
+
+//////////////////////////////////////////////////
+
+type config struct {
+	logger *slog.Logger
+
+	keepaliveInterval time.Duration
+	writeTimeout      time.Duration
+	clientBufferSize  int
+}
+
+var (
+	NilConfig  = errors.New("config is nil")
+	NilCrawler = errors.New("crawler is nil")
+)
+
+var (
+	DefaultKeepaliveInterval = 15 * time.Second
+	DefaultWriteTimeout      = 10 * time.Second
+	DefaultClientBufferSize  = 64
+)
+
+func validateConfig(cfg *config) error {
+	if cfg == nil {
+		return NilConfig
+	}
+
+	return nil
+}
+
+func buildServerFromConfig(cr *youtube.Crawler, cfg *config) (srv *Server, err error) {
+	if cfg == nil {
+		err = NilConfig
+		return
+	}
+
+	if cr == nil {
+		err = NilCrawler
+		return
+	}
+
+	srv = &Server{
+		cr:     cr,
+		logger: cfg.logger,
+
+		keepaliveInterval: cfg.keepaliveInterval,
+		writeTimeout:      cfg.writeTimeout,
+		clientBufferSize:  cfg.clientBufferSize,
+	}
+
+	if srv.keepaliveInterval <= 0 {
+		srv.keepaliveInterval = DefaultKeepaliveInterval
+	}
+	if srv.writeTimeout < 0 {
+		srv.writeTimeout = 0
+	} else if srv.writeTimeout == 0 {
+		srv.writeTimeout = DefaultWriteTimeout
+	}
+	if srv.clientBufferSize < 1 {
+		srv.clientBufferSize = DefaultClientBufferSize
+	}
+
+	return
+}
+
+type ConfigOption func(cfg *config)
+
+//////////////////////////////////////////////////
+
+func WithLogger(logger *slog.Logger) ConfigOption {
+	return func(cfg *config) {
+		cfg.logger = logger
+	}
+}
+
+// Sets the interval at which comment lines are sent to idle clients (to keep
+// intermediate proxies from closing the connection).
+func WithKeepaliveInterval(interval time.Duration) ConfigOption {
+	return func(cfg *config) {
+		cfg.keepaliveInterval = interval
+	}
+}
+
+// Sets the maximum time a single write to a client may take (a negative
+// value disables the deadline).
+func WithWriteTimeout(timeout time.Duration) ConfigOption {
+	return func(cfg *config) {
+		cfg.writeTimeout = timeout
+	}
+}
+
+// Sets the number of pending messages a client may fall behind by before it
+// gets evicted.
+func WithClientBufferSize(size int) ConfigOption {
+	return func(cfg *config) {
+		cfg.clientBufferSize = size
+	}
+}
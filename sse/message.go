@@ -0,0 +1,49 @@
+package sse
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+)
+
+//////////////////////////////////////////////////
+
+type message struct {
+	id      uint64
+	event   string
+	data    []byte
+	comment string
+}
+
+func (msg message) WriteTo(w io.Writer) (n int64, err error) {
+	var b bytes.Buffer
+
+	if msg.comment != "" {
+		b.WriteString(": ")
+		b.WriteString(strings.ReplaceAll(msg.comment, "\n", " "))
+		b.WriteString("\n")
+	}
+
+	if msg.event != "" {
+		if msg.id > 0 {
+			b.WriteString("id: ")
+			b.WriteString(strconv.FormatUint(msg.id, 10))
+			b.WriteString("\n")
+		}
+
+		b.WriteString("event: ")
+		b.WriteString(msg.event)
+		b.WriteString("\n")
+
+		for _, line := range bytes.Split(msg.data, []byte("\n")) {
+			b.WriteString("data: ")
+			b.Write(line)
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+
+	return b.WriteTo(w)
+}
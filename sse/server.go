@@ -0,0 +1,323 @@
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	youtube "github.com/rubpy/crawly-live-youtube"
+	"github.com/rubpy/crawly/clog"
+	"github.com/rubpy/crawly/csync"
+)
+
+//////////////////////////////////////////////////
+
+// Streams live state changes of a crawler's tracked handles to HTTP clients
+// as Server-Sent Events.
+//
+// Every client receives a "snapshot" event upon connecting, followed by one
+// event per change (named after youtube.EventKind). Clients may narrow the
+// stream down with one or more "handle" query parameters (channel IDs,
+// channel URLs or "@handles").
+type Server struct {
+	cr      *youtube.Crawler
+	logger  *slog.Logger
+	tracker youtube.StateTracker
+
+	keepaliveInterval time.Duration
+	writeTimeout      time.Duration
+	clientBufferSize  int
+
+	clients csync.Map[*client, struct{}]
+	running atomic.Bool
+	seq     atomic.Uint64
+}
+
+func NewServer(cr *youtube.Crawler, opts ...ConfigOption) (*Server, error) {
+	var cfg config
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := validateConfig(&cfg); err != nil {
+		return nil, err
+	}
+
+	srv, err := buildServerFromConfig(cr, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return srv, nil
+}
+
+var (
+	AlreadyRunning        = errors.New("already running")
+	StreamingNotSupported = errors.New("streaming not supported")
+)
+
+//////////////////////////////////////////////////
+
+func (srv *Server) Log(ctx context.Context, params clog.Params) {
+	if srv.logger == nil {
+		return
+	}
+
+	clog.WithParams(srv.logger, ctx, params)
+}
+
+// Returns the number of currently connected clients.
+func (srv *Server) Clients() (n int) {
+	srv.clients.Range(func(_ *client, _ struct{}) bool {
+		n++
+
+		return true
+	})
+
+	return
+}
+
+// Consumes crawler results until ctx is done or the crawler's result channel
+// gets closed, and fans out the resulting events to connected clients.
+func (srv *Server) Run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if srv.running.Swap(true) {
+		return AlreadyRunning
+	}
+	defer srv.running.Store(false)
+
+	l := srv.cr.Listen()
+	defer l.Discard()
+
+	defer srv.clients.Range(func(c *client, _ struct{}) bool {
+		srv.evict(c)
+
+		return true
+	})
+
+	ch := l.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case result, ok := <-ch:
+			if !ok {
+				// Result channel has been closed.
+
+				return nil
+			}
+
+			for _, event := range srv.tracker.Update(result) {
+				srv.publish(ctx, event)
+			}
+		}
+	}
+}
+
+func (srv *Server) publish(ctx context.Context, event youtube.Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		srv.Log(ctx, clog.Params{
+			Message: "publish",
+			Level:   slog.LevelError,
+			Err:     fmt.Errorf("json.Marshal: %w", err),
+		})
+
+		return
+	}
+
+	msg := message{
+		id:    srv.seq.Add(1),
+		event: event.Kind.String(),
+		data:  data,
+	}
+
+	srv.clients.Range(func(c *client, _ struct{}) bool {
+		if !c.accepts(srv.cr, event.State.Handle) {
+			return true
+		}
+
+		select {
+		case c.ch <- msg:
+		default:
+			// NOTE: never block the listener on a single slow client.
+			srv.evict(c)
+
+			srv.Log(ctx, clog.Params{
+				Message: "evict",
+				Level:   slog.LevelWarn,
+
+				Values: clog.ParamGroup{
+					"remoteAddr": c.remoteAddr,
+				},
+			})
+		}
+
+		return true
+	})
+}
+
+func (srv *Server) evict(c *client) {
+	srv.clients.Delete(c)
+	c.close()
+}
+
+//////////////////////////////////////////////////
+
+func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var filter []youtube.Handle
+	for _, v := range r.URL.Query()["handle"] {
+		handle, err := youtube.ParseHandle(v)
+		if err != nil {
+			http.Error(w, "invalid handle: "+strconv.Quote(v), http.StatusBadRequest)
+			return
+		}
+
+		filter = append(filter, handle)
+	}
+
+	rc := http.NewResponseController(w)
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	ctx := r.Context()
+	c := &client{
+		ch:         make(chan message, srv.clientBufferSize),
+		done:       make(chan struct{}),
+		filter:     filter,
+		remoteAddr: r.RemoteAddr,
+	}
+
+	srv.clients.Store(c, struct{}{})
+	defer srv.evict(c)
+
+	write := func(msg message) error {
+		if srv.writeTimeout > 0 {
+			_ = rc.SetWriteDeadline(time.Now().Add(srv.writeTimeout))
+		}
+
+		if _, err := msg.WriteTo(w); err != nil {
+			return err
+		}
+
+		if err := rc.Flush(); err != nil {
+			if errors.Is(err, http.ErrNotSupported) {
+				return StreamingNotSupported
+			}
+
+			return err
+		}
+
+		return nil
+	}
+
+	{
+		states := []youtube.LiveState{}
+		for _, state := range srv.tracker.Snapshot() {
+			if c.accepts(srv.cr, state.Handle) {
+				states = append(states, state)
+			}
+		}
+
+		data, err := json.Marshal(states)
+		if err == nil {
+			err = write(message{
+				id:    srv.seq.Load(),
+				event: "snapshot",
+				data:  data,
+			})
+		}
+
+		if err != nil {
+			srv.Log(ctx, clog.Params{
+				Message: "snapshot",
+				Level:   slog.LevelError,
+				Err:     err,
+
+				Values: clog.ParamGroup{
+					"remoteAddr": c.remoteAddr,
+				},
+			})
+
+			return
+		}
+	}
+
+	keepalive := time.NewTicker(srv.keepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		var err error
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-c.done:
+			return
+
+		case msg := <-c.ch:
+			err = write(msg)
+
+		case <-keepalive.C:
+			err = write(message{comment: "keepalive"})
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+//////////////////////////////////////////////////
+
+type client struct {
+	ch         chan message
+	done       chan struct{}
+	closeOnce  sync.Once
+	filter     []youtube.Handle
+	remoteAddr string
+}
+
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+func (c *client) accepts(cr *youtube.Crawler, handle youtube.Handle) bool {
+	if len(c.filter) == 0 {
+		return true
+	}
+
+	for _, h := range c.filter {
+		if cr.CanonicalHandle(h) == handle {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,104 @@
+package youtube
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/rubpy/crawly-live-youtube/xmlapi"
+)
+
+//////////////////////////////////////////////////
+
+// Lists the videos on a channel's "Live" tab (i.e., its current, upcoming
+// and past livestreams), as found in the page's ytInitialData.
+func (cr *Crawler) FetchChannelStreams(ctx context.Context, channelID string) (videos []xmlapi.PageVideo, err error) {
+	if channelID == "" || !IsValidChannelID(channelID) {
+		err = InvalidChannelID
+		return
+	}
+
+	if cr.client == nil {
+		err = NilClient
+		return
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	} else {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+	}
+
+	streamsURL := &url.URL{
+		Scheme: "https",
+		Host:   "www.youtube.com",
+		Path:   "/channel/" + channelID + "/streams",
+	}
+
+	profile := cr.loadRequestProfile()
+	profile.applyQuery(EndpointChannelStreams, streamsURL)
+
+	rawStreamsURL := streamsURL.String()
+
+	resp, err := cr.request(ctx, EndpointChannelStreams, rawStreamsURL, profile.header(EndpointChannelStreams, cr.now()))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	limit := cr.loadEffectiveSettings().MaximumResponseBodySize
+
+	if resp.StatusCode != 200 {
+		body, err := readBody(resp.Body, limit)
+		if err != nil {
+			return nil, newFetchError(EndpointChannelStreams, rawStreamsURL, resp.StatusCode, body, err)
+		}
+
+		cause := UnexpectedStatus
+		if isTerminatedPage(body) {
+			cause = ChannelTerminated
+		}
+
+		return nil, cr.responseError(ctx, EndpointChannelStreams, rawStreamsURL, resp, body, cause)
+	}
+
+	captured := newCapturedBody()
+	found, err := xmlapi.ExtractPageDataReader(io.TeeReader(resp.Body, captured), limit, xmlapi.InitialData)
+	if err != nil {
+		return nil, cr.responseError(ctx, EndpointChannelStreams, rawStreamsURL, resp, captured.Bytes(), err)
+	}
+	cr.recordSuccess(ctx, EndpointChannelStreams)
+
+	return found[xmlapi.InitialData].Videos(), nil
+}
+
+// Returns the live and upcoming streams of a channel's "Live" tab as video
+// candidates.
+func (cr *Crawler) streamVideoCandidates(ctx context.Context, channelID string) (vcs []VideoCandidate, err error) {
+	videos, err := cr.FetchChannelStreams(ctx, channelID)
+	if err != nil {
+		return
+	}
+
+	for _, vid := range videos {
+		if !vid.Live && !vid.Upcoming {
+			continue
+		}
+
+		vc := VideoCandidate{
+			ID:        vid.ID,
+			ChannelID: channelID,
+			Title:     vid.Title,
+		}
+		if vid.ScheduledStartTime != 0 {
+			vc.ScheduledStartTime = time.UnixMilli(vid.ScheduledStartTime)
+		}
+
+		vcs = append(vcs, vc)
+	}
+
+	return
+}
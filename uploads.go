@@ -0,0 +1,200 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+//////////////////////////////////////////////////
+
+// A video of a channel's uploads playlist.
+type Upload struct {
+	VideoID   string    `json:"video_id"`
+	Published time.Time `json:"published"` // (zero if missing or invalid)
+}
+
+// Maximum number of items per playlistItems.list page.
+const maximumPlaylistItemsPageSize = 50
+
+// Returns the ID of a channel's uploads playlist (via channels.list; cached).
+func (cr *Crawler) FetchUploadsPlaylistID(ctx context.Context, channelID string) (playlistID string, err error) {
+	if channelID == "" || !IsValidChannelID(channelID) {
+		err = InvalidChannelID
+		return
+	}
+
+	if playlistID, ok := cr.uploadsPlaylistCache.Load(channelID); ok {
+		return playlistID, nil
+	}
+
+	if cr.service == nil {
+		err = NilService
+		return
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	} else {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+	}
+
+	call := cr.service.Channels.List([]string{"contentDetails"})
+	call.Context(ctx)
+	call.Id(channelID)
+
+	cr.recordQuota("channels.list", quotaCostList)
+	resp, err := call.Do()
+	if err != nil {
+		err = fmt.Errorf("youtube.ChannelsService.List: %w", err)
+		return
+	}
+
+	for _, item := range resp.Items {
+		if item.Id != channelID || item.ContentDetails == nil || item.ContentDetails.RelatedPlaylists == nil {
+			continue
+		}
+
+		playlistID = item.ContentDetails.RelatedPlaylists.Uploads
+		if IsValidPlaylistID(playlistID) {
+			cr.uploadsPlaylistCache.Store(channelID, playlistID)
+			return playlistID, nil
+		}
+	}
+
+	err = fmt.Errorf("%w: no uploads playlist", InvalidChannelID)
+	return
+}
+
+// Lists (up to depth of) the latest uploads of a channel, via its uploads
+// playlist (using playlistItems.list, which costs a quota unit per page of
+// up to 50 videos).
+func (cr *Crawler) FetchUploads(ctx context.Context, channelID string, depth int) (uploads []Upload, err error) {
+	if depth <= 0 {
+		return
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	playlistID, err := cr.FetchUploadsPlaylistID(ctx, channelID)
+	if err != nil {
+		return
+	}
+
+	if cr.service == nil {
+		err = NilService
+		return
+	}
+
+	pageToken := ""
+	for len(uploads) < depth {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		call := cr.service.PlaylistItems.List([]string{"contentDetails"})
+		call.Context(ctx)
+		call.PlaylistId(playlistID)
+		call.MaxResults(int64(min(depth-len(uploads), maximumPlaylistItemsPageSize)))
+		if pageToken != "" {
+			call.PageToken(pageToken)
+		}
+
+		cr.recordQuota("playlistItems.list", quotaCostList)
+		resp, err := call.Do()
+		if err != nil {
+			return uploads, fmt.Errorf("youtube.PlaylistItemsService.List: %w", err)
+		}
+
+		for _, item := range resp.Items {
+			if item.ContentDetails == nil || !IsValidVideoID(item.ContentDetails.VideoId) {
+				continue
+			}
+
+			published, _ := parseAPITime(item.ContentDetails.VideoPublishedAt)
+			uploads = append(uploads, Upload{
+				VideoID:   item.ContentDetails.VideoId,
+				Published: published,
+			})
+		}
+
+		pageToken = resp.NextPageToken
+		if pageToken == "" || len(resp.Items) == 0 {
+			break
+		}
+	}
+
+	if len(uploads) > depth {
+		uploads = uploads[:depth]
+	}
+
+	return
+}
+
+// Calls FetchUploads, unless its (estimated) quota cost would exceed
+// DailyQuotaBudget.
+func (cr *Crawler) fetchUploadsWithinBudget(ctx context.Context, channelID string, settings CrawlerSettings) (uploads []Upload, err error) {
+	calls := (settings.UploadsDepth + maximumPlaylistItemsPageSize - 1) / maximumPlaylistItemsPageSize
+	if _, ok := cr.uploadsPlaylistCache.Load(channelID); !ok {
+		calls++
+	}
+
+	if err = cr.checkQuota(int64(calls)*quotaCostList, settings); err != nil {
+		return
+	}
+
+	return cr.FetchUploads(ctx, channelID, settings.UploadsDepth)
+}
+
+// Appends the candidates of b which are not already in a.
+func mergeVideoCandidates(a []VideoCandidate, b []VideoCandidate) []VideoCandidate {
+	for _, vc := range b {
+		found := false
+		for idx := range a {
+			existing := &a[idx]
+			if existing.ID == vc.ID {
+				if existing.Title == "" {
+					existing.Title = vc.Title
+				}
+				if existing.Description == "" {
+					existing.Description = vc.Description
+				}
+				if existing.ThumbnailURL == "" {
+					existing.ThumbnailURL = vc.ThumbnailURL
+				}
+				if existing.ScheduledStartTime.IsZero() {
+					existing.ScheduledStartTime = vc.ScheduledStartTime
+				}
+
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			a = append(a, vc)
+		}
+	}
+
+	return a
+}
+
+// Replaces each of vcs with the candidate of the same ID in prev, if any (so
+// that refetching candidates keeps their check state), filling in its
+// missing fields from the new one.
+func keepVideoCandidates(vcs []VideoCandidate, prev []VideoCandidate) []VideoCandidate {
+	for idx := range vcs {
+		for _, existing := range prev {
+			if existing.ID == vcs[idx].ID {
+				vcs[idx] = mergeVideoCandidates([]VideoCandidate{existing}, vcs[idx:idx+1])[0]
+				break
+			}
+		}
+	}
+
+	return vcs
+}
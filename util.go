@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -27,18 +28,82 @@ var nonceKey = "_h"
 
 // Generates a time-based unique string, generally used for signing HTTP
 // requests (to bypass caching mechanisms).
-func generateNonce() string {
-	return uniqueHex()
+func generateNonce(now time.Time) string {
+	return uniqueHex(now)
 }
 
-func uniqueHex() string {
-	return fmt.Sprintf("%016x", uniqueUint64())
+func uniqueHex(now time.Time) string {
+	return fmt.Sprintf("%016x", uniqueUint64(now))
 }
 
-func uniqueUint64() uint64 {
-	v := uint64(time.Now().UnixMilli())
+func uniqueUint64(now time.Time) uint64 {
+	v := uint64(now.UnixMilli())
 	r := uint64(rand.Uint32()) & 0x3fffff
 	v = (v << 22) | r
 
 	return v
 }
+
+// Parses a timestamp (RFC3339) as returned by the YouTube Data API.
+func parseAPITime(s string) (t time.Time, ok bool) {
+	if s == "" {
+		return
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// Parses a duration (ISO 8601, e.g. "PT1H2M3S" or "P0D") as returned by the
+// YouTube Data API.
+func parseAPIDuration(s string) (d time.Duration, ok bool) {
+	s, ok = strings.CutPrefix(s, "P")
+	if !ok || s == "" {
+		return 0, false
+	}
+
+	units := map[byte]time.Duration{
+		'W': 7 * 24 * time.Hour,
+		'D': 24 * time.Hour,
+	}
+
+	var n int64
+	digits := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			n = n*10 + int64(c-'0')
+			digits = true
+
+		case c == 'T':
+			if digits {
+				return 0, false
+			}
+			units = map[byte]time.Duration{
+				'H': time.Hour,
+				'M': time.Minute,
+				'S': time.Second,
+			}
+
+		default:
+			unit, known := units[c]
+			if !known || !digits {
+				return 0, false
+			}
+
+			d += time.Duration(n) * unit
+			n, digits = 0, false
+		}
+	}
+
+	if digits {
+		return 0, false
+	}
+
+	return d, true
+}
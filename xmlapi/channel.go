@@ -5,6 +5,7 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 
@@ -15,7 +16,20 @@ import (
 //////////////////////////////////////////////////
 
 type ChannelIndex struct {
-	ChannelID string
+	ChannelID string `json:"channel_id"`
+
+	// Metadata of the channel (empty, if not found on the page).
+	Title       string `json:"title,omitempty"`
+	Handle      string `json:"handle,omitempty"` // (e.g., "@LofiGirl")
+	Description string `json:"description,omitempty"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+	BannerURL   string `json:"banner_url,omitempty"`
+	Verified    bool   `json:"verified,omitempty"`
+
+	// Subscriber count, as displayed (e.g., "1.23M subscribers"), and its
+	// (approximate) value; the latter is 0 if the count is hidden.
+	SubscriberCountText string `json:"subscriber_count_text,omitempty"`
+	Subscribers         int64  `json:"subscribers,omitempty"`
 }
 
 func (res ChannelIndex) String() string {
@@ -23,6 +37,10 @@ func (res ChannelIndex) String() string {
 
 	s.WriteString("{ChannelIndex:[channelID:")
 	s.WriteString(strconv.Quote(res.ChannelID))
+	if res.Handle != "" {
+		s.WriteString(", handle:")
+		s.WriteString(strconv.Quote(res.Handle))
+	}
 	s.WriteString("]}")
 
 	return s.String()
@@ -33,148 +51,168 @@ func ParseChannelIndex(b []byte) (*ChannelIndex, error) {
 		return nil, errors.New("b is empty")
 	}
 
-	p := b[:]
-	linkTagStart := []byte("<link")
-	linkTagEnd := []byte(">")
+	return parseChannelIndex(bytes.NewReader(b), 0, true)
+}
 
-	linkTags := []byte("<html><body>")
+// Parses a channel page as it is being read, and stops reading as soon as
+// the channel ID has been found (so only the metadata preceding it, if any,
+// is filled in); fails with BodyTooLarge if more than limit bytes would have
+// to be read (a limit of 0 or less means no limit).
+func ParseChannelIndexReader(r io.Reader, limit int64) (*ChannelIndex, error) {
+	return parseChannelIndex(r, limit, false)
+}
 
-	for {
-		startPos := bytes.Index(p, linkTagStart)
-		if startPos < 0 {
-			break
-		}
-		p = p[startPos:]
+// Like ParseChannelIndexReader, but reads on until the channel's metadata
+// (from <meta> tags and ytInitialData) has been found.
+func ParseChannelPageReader(r io.Reader, limit int64) (*ChannelIndex, error) {
+	return parseChannelIndex(r, limit, true)
+}
 
-		endPos := bytes.Index(p, linkTagEnd)
-		if endPos < 0 {
-			break
-		}
-		linkTag := p[:endPos+1]
-		p = p[endPos+1:]
+func parseChannelIndex(r io.Reader, limit int64, metadata bool) (*ChannelIndex, error) {
+	if r == nil {
+		return nil, errors.New("r is nil")
+	}
 
-		if len(linkTag) > len(linkTagStart)+len(linkTagEnd) {
-			linkTags = append(linkTags, linkTag...)
+	index := &ChannelIndex{}
+	done := func() (*ChannelIndex, error) {
+		if index.ChannelID == "" {
+			return nil, errors.New("failed HTML extraction")
 		}
+
+		return index, nil
 	}
 
-	linkTags = append(linkTags, []byte("</body></html>")...)
+	z := html.NewTokenizer(newLimitedReader(r, limit))
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			err := z.Err()
+			if err == io.EOF {
+				return done()
+			}
+
+			return nil, fmt.Errorf("failed HTML extraction: %w", err)
 
-	extract := func(b []byte) (channelID string, err error) {
-		root, err := html.Parse(bytes.NewReader(b))
-		if err != nil || root == nil {
-			return
-		}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
 
-		skipIntoChild := func(node *html.Node, nodeType html.NodeType, atomType atom.Atom) *html.Node {
-			for {
-				if node == nil {
-					return nil
+			switch atom.Lookup(name) {
+			case atom.Link:
+				if !hasAttr {
+					continue
 				}
 
-				if (nodeType == 0 || node.Type == nodeType) && (atomType == 0 || node.DataAtom == atomType) {
-					node = node.FirstChild
-					break
+				channelID, handle, title := linkChannelID(z)
+				if index.ChannelID == "" && channelID != "" {
+					index.ChannelID = channelID
+
+					if !metadata {
+						return index, nil
+					}
+				}
+				if index.Handle == "" {
+					index.Handle = handle
+				}
+				if index.Title == "" {
+					index.Title = title
 				}
 
-				node = node.NextSibling
-			}
+			case atom.Meta:
+				if hasAttr {
+					index.applyMetaTag(z)
+				}
+
+			case atom.Script:
+				if !metadata || tt == html.SelfClosingTagToken || z.Next() != html.TextToken {
+					continue
+				}
 
-			return node
+				if data, ok := extractScriptObject(string(z.Text()), "ytInitialData"); ok {
+					index.applyInitialData(data)
+
+					// NOTE: nothing of interest follows ytInitialData.
+					return done()
+				}
+			}
 		}
+	}
+}
+
+// Extracts a channel ID from the attributes of a <link> tag (either the
+// canonical URL of the channel, or the URL of its RSS feed), as well as the
+// channel's handle or title (from itemprop="url" or itemprop="name" tags).
+func linkChannelID(z *html.Tokenizer) (channelID string, handle string, title string) {
+	var attrs struct {
+		rel      string
+		itemprop string
+		typ      string
+		href     string
+		content  string
+	}
 
-		node := skipIntoChild(root, html.DocumentNode, 0)
-		if node == nil {
-			return
+	for {
+		key, val, more := z.TagAttr()
+
+		switch strings.ToLower(string(key)) {
+		case "rel":
+			attrs.rel = strings.ToLower(string(val))
+		case "itemprop":
+			attrs.itemprop = strings.ToLower(string(val))
+		case "type":
+			attrs.typ = strings.ToLower(string(val))
+		case "href":
+			attrs.href = strings.TrimSpace(string(val))
+		case "content":
+			attrs.content = strings.TrimSpace(string(val))
 		}
 
-		node = skipIntoChild(node, html.ElementNode, atom.Html)
-		if node == nil {
-			return
+		if !more {
+			break
 		}
+	}
 
-		node = skipIntoChild(node, html.ElementNode, atom.Body)
-		if node == nil {
-			return
+	if attrs.itemprop == "name" {
+		return "", "", attrs.content
+	}
+
+	if attrs.rel == "canonical" || attrs.itemprop == "url" {
+		if h := handleFromURL(attrs.href); h != "" {
+			return "", h, ""
 		}
 
-		for node != nil {
-			var attrs struct {
-				rel      string
-				itemprop string
-				typ      string
-				href     string
-			}
+		marker := "channel/"
+		markerPos := strings.Index(attrs.href, marker)
+		if markerPos > 0 {
+			s := attrs.href[markerPos+len(marker):]
 
-			if node.Type != html.ElementNode || node.DataAtom != atom.Link || len(node.Attr) == 0 {
-				goto nextNode
+			markerPos = strings.Index(s, "/")
+			if markerPos > 0 {
+				s = s[:markerPos]
 			}
 
-			for _, attr := range node.Attr {
-				k := strings.ToLower(attr.Key)
-
-				switch k {
-				case "rel":
-					attrs.rel = strings.ToLower(attr.Val)
-				case "itemprop":
-					attrs.itemprop = strings.ToLower(attr.Val)
-				case "type":
-					attrs.typ = strings.ToLower(attr.Val)
-				case "href":
-					attrs.href = strings.TrimSpace(attr.Val)
-				}
+			if IsValidChannelID(s) {
+				return s, "", ""
 			}
-
-			if attrs.rel == "canonical" || attrs.itemprop == "url" {
-				marker := "channel/"
-				markerPos := strings.Index(attrs.href, marker)
-				if markerPos > 0 {
-					s := attrs.href[markerPos+len(marker):]
-
-					markerPos = strings.Index(s, "/")
-					if markerPos > 0 {
-						s = s[:markerPos]
-					}
-
-					if IsValidChannelID(s) {
-						return s, nil
-					}
-				}
-			} else if attrs.rel == "alternate" && strings.Contains(attrs.typ, "rss") {
-				marker := "channel_id="
-				markerPos := strings.Index(attrs.href, marker)
-				if markerPos > 0 {
-					s := attrs.href[markerPos+len(marker):]
-
-					markerPos = strings.Index(s, "&")
-					if markerPos > 0 {
-						s = s[:markerPos]
-					}
-
-					if IsValidChannelID(s) {
-						return s, nil
-					}
-				}
+		}
+	} else if attrs.rel == "alternate" && strings.Contains(attrs.typ, "rss") {
+		marker := "channel_id="
+		markerPos := strings.Index(attrs.href, marker)
+		if markerPos > 0 {
+			s := attrs.href[markerPos+len(marker):]
+
+			markerPos = strings.Index(s, "&")
+			if markerPos > 0 {
+				s = s[:markerPos]
 			}
 
-		nextNode:
-			node = node.NextSibling
+			if IsValidChannelID(s) {
+				return s, "", ""
+			}
 		}
-
-		return
 	}
 
-	channelID, err := extract(linkTags)
-	if err != nil {
-		return nil, fmt.Errorf("failed HTML extraction: %w", err)
-	}
-	if channelID == "" {
-		return nil, errors.New("failed HTML extraction")
-	}
-
-	return &ChannelIndex{
-		ChannelID: channelID,
-	}, nil
+	return "", "", ""
 }
 
 //////////////////////////////////////////////////
@@ -199,21 +237,57 @@ type ChannelFeed struct {
 
 	Links []Link `xml:"link,omitempty"`
 
-	Title     string  `xml:"title"`
-	Author    *Author `xml:"author,omitempty"`
-	Published string  `xml:"published"`
+	ChannelID string `xml:"channelId"`
+	// Set if this is the feed of a playlist (rather than of a channel), in
+	// which case ChannelID is that of the playlist's owner.
+	PlaylistID string  `xml:"playlistId"`
+	Title      string  `xml:"title"`
+	Author     *Author `xml:"author,omitempty"`
+	Published  string  `xml:"published"`
 
 	Entries []ChannelFeedEntry `xml:"entry,omitempty"`
 }
 
+type ChannelFeedChannel struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+
+	Published int64 `json:"published"` // (unix timestamp; in milliseconds)
+}
+
+func (ch ChannelFeedChannel) String() string {
+	var s strings.Builder
+
+	s.WriteString("{ChannelFeedChannel:[id:")
+	s.WriteString(strconv.Quote(ch.ID))
+	s.WriteString(", title:")
+	s.WriteString(strconv.Quote(ch.Title))
+	s.WriteString("]}")
+
+	return s.String()
+}
+
 type ChannelFeedVideo struct {
 	ID        string `json:"id"`
 	ChannelID string `json:"channel_id"`
 
-	Title        string `json:"title"`
-	Description  string `json:"description"`
-	URL          string `json:"url"`
-	ThumbnailURL string `json:"thumbnail_url"`
+	// Title of the channel, and name and URI of the video's author (which,
+	// in practice, are the channel's title and URL).
+	ChannelTitle string `json:"channel_title"`
+	AuthorName   string `json:"author_name"`
+	AuthorURI    string `json:"author_uri"`
+
+	Title           string `json:"title"`
+	Description     string `json:"description"`
+	URL             string `json:"url"`
+	ThumbnailURL    string `json:"thumbnail_url"`
+	ThumbnailWidth  int    `json:"thumbnail_width"`
+	ThumbnailHeight int    `json:"thumbnail_height"`
+
+	Content *MediaContent    `json:"content,omitempty"`
+	Views   int64            `json:"views"`
+	Rating  *MediaStarRating `json:"rating,omitempty"`
 
 	Published int64 `json:"published"` // (unix timestamp; in milliseconds)
 	Updated   int64 `json:"updated"`   // (unix timestamp; in milliseconds)
@@ -231,6 +305,37 @@ func (vid ChannelFeedVideo) String() string {
 	return s.String()
 }
 
+// Returns the feed-level details of the channel.
+func (f *ChannelFeed) Channel() (ch ChannelFeedChannel) {
+	if f == nil {
+		return
+	}
+
+	ch.ID = f.ChannelID
+	ch.Title = f.Title
+	ch.Published, _ = parseDate(f.Published)
+
+	if f.Author != nil {
+		if ch.Title == "" {
+			ch.Title = f.Author.Name
+		}
+		if isValidURL(f.Author.URI) {
+			ch.URL = f.Author.URI
+		}
+	}
+
+	if ch.URL == "" {
+		for _, link := range f.Links {
+			if strings.ToLower(link.Rel) == "alternate" && isValidURL(link.Href) {
+				ch.URL = link.Href
+				break
+			}
+		}
+	}
+
+	return
+}
+
 func (f *ChannelFeed) Videos() (videos []ChannelFeedVideo) {
 	videos = make([]ChannelFeedVideo, 0)
 
@@ -238,6 +343,8 @@ func (f *ChannelFeed) Videos() (videos []ChannelFeedVideo) {
 		return
 	}
 
+	channel := f.Channel()
+
 	ids := make([]string, 0)
 	for _, entry := range f.Entries {
 		if len(entry.VideoID) == 0 {
@@ -263,6 +370,8 @@ func (f *ChannelFeed) Videos() (videos []ChannelFeedVideo) {
 			ID:        videoID,
 			ChannelID: entry.ChannelID,
 
+			ChannelTitle: channel.Title,
+
 			Title:        entry.Title,
 			Description:  "",
 			URL:          "https://www.youtube.com/watch?v=" + videoID,
@@ -272,13 +381,35 @@ func (f *ChannelFeed) Videos() (videos []ChannelFeedVideo) {
 			Updated:   updated,
 		}
 
+		if f.PlaylistID != "" {
+			// NOTE: videos of a playlist may belong to other channels.
+			vid.ChannelTitle = ""
+		} else if vid.ChannelID == "" {
+			vid.ChannelID = channel.ID
+		}
+
+		if author := entry.Author; author != nil || f.Author != nil {
+			if author == nil {
+				author = f.Author
+			}
+
+			vid.AuthorName = author.Name
+			if isValidURL(author.URI) {
+				vid.AuthorURI = author.URI
+			}
+
+			if vid.ChannelTitle == "" && entry.Author != nil {
+				vid.ChannelTitle = entry.Author.Name
+			}
+		}
+
 		if len(entry.Links) > 0 {
 			for _, link := range entry.Links {
 				if len(link.Href) == 0 || strings.ToLower(link.Rel) != "alternate" {
 					continue
 				}
 
-				if isValidURL(link.Href) {
+				if !isValidURL(link.Href) {
 					continue
 				}
 
@@ -287,15 +418,29 @@ func (f *ChannelFeed) Videos() (videos []ChannelFeedVideo) {
 			}
 		}
 
-		if len(entry.MediaGroups) > 0 {
-			for _, mg := range entry.MediaGroups {
+		for _, mg := range entry.MediaGroups {
+			if vid.Description == "" {
 				vid.Description = mg.Description
+			}
 
-				if mg.Thumbnail != nil && len(mg.Thumbnail.URL) > 0 {
-					if isValidURL(mg.Thumbnail.URL) {
-						vid.ThumbnailURL = mg.Thumbnail.URL
-						break
-					}
+			if vid.ThumbnailURL == "" && mg.Thumbnail != nil && isValidURL(mg.Thumbnail.URL) {
+				vid.ThumbnailURL = mg.Thumbnail.URL
+				vid.ThumbnailWidth = mg.Thumbnail.Width
+				vid.ThumbnailHeight = mg.Thumbnail.Height
+			}
+
+			if vid.Content == nil && mg.Content != nil && isValidURL(mg.Content.URL) {
+				content := *mg.Content
+				vid.Content = &content
+			}
+
+			if mg.Community != nil {
+				if mg.Community.Statistics != nil && vid.Views == 0 {
+					vid.Views = mg.Community.Statistics.Views
+				}
+				if mg.Community.StarRating != nil && vid.Rating == nil {
+					rating := *mg.Community.StarRating
+					vid.Rating = &rating
 				}
 			}
 		}
@@ -320,6 +465,25 @@ func ParseChannelFeed(b []byte) (*ChannelFeed, error) {
 	return feed, nil
 }
 
+// Decodes a channel feed as it is being read; fails with BodyTooLarge if it
+// is longer than limit bytes (a limit of 0 or less means no limit).
+func ParseChannelFeedReader(r io.Reader, limit int64) (*ChannelFeed, error) {
+	if r == nil {
+		return nil, errors.New("r is nil")
+	}
+
+	feed := &ChannelFeed{}
+	if err := xml.NewDecoder(newLimitedReader(r, limit)).Decode(feed); err != nil {
+		if err == io.EOF {
+			return nil, errors.New("feed is empty")
+		}
+
+		return nil, err
+	}
+
+	return feed, nil
+}
+
 // Checks (roughly) if the given string is a valid YouTube channel ID.
 func IsValidChannelID(s string) bool {
 	n := len(s)
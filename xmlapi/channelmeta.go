@@ -0,0 +1,215 @@
+package xmlapi
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+//////////////////////////////////////////////////
+
+// Fills in metadata from a <meta> tag (Open Graph or plain), unless already
+// known.
+func (index *ChannelIndex) applyMetaTag(z *html.Tokenizer) {
+	var key, content string
+	for {
+		k, v, more := z.TagAttr()
+
+		switch strings.ToLower(string(k)) {
+		case "property", "name", "itemprop":
+			if key == "" {
+				key = strings.ToLower(string(v))
+			}
+		case "content":
+			content = strings.TrimSpace(string(v))
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	if content == "" {
+		return
+	}
+
+	set := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+
+	switch key {
+	case "og:title", "title":
+		set(&index.Title, content)
+	case "og:description", "description":
+		set(&index.Description, content)
+	case "og:image":
+		if isValidURL(content) {
+			set(&index.AvatarURL, content)
+		}
+	case "og:url":
+		set(&index.Handle, handleFromURL(content))
+	}
+}
+
+// Fills in metadata from a channel page's ytInitialData (overriding what has
+// been found in <meta> tags, which may be truncated).
+func (index *ChannelIndex) applyInitialData(data map[string]any) {
+	set := func(field *string, value string) {
+		if value != "" {
+			*field = value
+		}
+	}
+
+	if md := lookup(data, "metadata", "channelMetadataRenderer"); md != nil {
+		if channelID := lookupString(md, "externalId"); index.ChannelID == "" && IsValidChannelID(channelID) {
+			index.ChannelID = channelID
+		}
+
+		set(&index.Title, lookupString(md, "title"))
+		set(&index.Description, lookupString(md, "description"))
+		set(&index.AvatarURL, lookupImageURL(md, "avatar"))
+		set(&index.Handle, handleFromURL(lookupString(md, "vanityChannelUrl")))
+	}
+
+	header := lookup(data, "header")
+
+	// NOTE: older layout.
+	if h := lookup(header, "c4TabbedHeaderRenderer"); h != nil {
+		set(&index.Title, lookupString(h, "title"))
+		set(&index.AvatarURL, lookupImageURL(h, "avatar"))
+		set(&index.BannerURL, lookupImageURL(h, "banner"))
+		set(&index.SubscriberCountText, lookupText(h, "subscriberCountText"))
+
+		if handle := lookupText(h, "channelHandleText"); strings.HasPrefix(handle, "@") {
+			index.Handle = handle
+		}
+	}
+
+	// NOTE: newer layout.
+	if h := lookup(header, "pageHeaderRenderer", "content", "pageHeaderViewModel"); h != nil {
+		set(&index.Title, lookupText(h, "title", "dynamicTextViewModel", "text"))
+		set(&index.AvatarURL, lookupImageURL(h, "image", "decoratedAvatarViewModel", "avatar", "avatarViewModel", "image"))
+		set(&index.BannerURL, lookupImageURL(h, "banner", "imageBannerViewModel", "image"))
+
+		rows, _ := lookup(h, "metadata", "contentMetadataViewModel", "metadataRows").([]any)
+		for _, row := range rows {
+			parts, _ := lookup(row, "metadataParts").([]any)
+			for _, part := range parts {
+				text := lookupText(part, "text")
+
+				switch {
+				case strings.HasPrefix(text, "@"):
+					index.Handle = text
+				case strings.Contains(strings.ToLower(text), "subscriber"):
+					index.SubscriberCountText = text
+				}
+			}
+		}
+	}
+
+	walkStrings(header, func(s string) bool {
+		if strings.HasPrefix(s, "BADGE_STYLE_TYPE_VERIFIED") || s == "CHECK_CIRCLE_FILLED" {
+			index.Verified = true
+			return false
+		}
+
+		return true
+	})
+
+	if index.SubscriberCountText != "" {
+		index.Subscribers, _ = ParseApproximateCount(index.SubscriberCountText)
+	}
+}
+
+// Returns the handle (e.g., "@LofiGirl") from a channel URL of the form
+// "https://www.youtube.com/@LofiGirl", or an empty string.
+func handleFromURL(s string) string {
+	pos := strings.Index(s, "/@")
+	if pos < 0 {
+		return ""
+	}
+
+	handle := s[pos+1:]
+	if end := strings.IndexAny(handle, "/?#"); end >= 0 {
+		handle = handle[:end]
+	}
+	if len(handle) < 2 {
+		return ""
+	}
+
+	return handle
+}
+
+// Parses a count as abbreviated by YouTube in English (e.g., "1.23M
+// subscribers", "950K views", "1,234 subscribers"); returns false if s does
+// not begin with such a number (e.g., with a decimal comma, as in "1,23 Mio.").
+func ParseApproximateCount(s string) (n int64, ok bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= '0' && r <= '9') && r != '.' && r != ','
+	})
+	if end == 0 {
+		return 0, false
+	}
+	if end < 0 {
+		end = len(s)
+	}
+
+	number := s[:end]
+	if !validCountNumber(number) {
+		return 0, false
+	}
+	number = strings.ReplaceAll(number, ",", "")
+	multiplier := 1.0
+	if end < len(s) {
+		switch s[end] {
+		case 'K', 'k':
+			multiplier = 1e3
+		case 'M':
+			multiplier = 1e6
+		case 'B':
+			multiplier = 1e9
+		}
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return int64(math.Round(f * multiplier)), true
+}
+
+// Whether s is a number as written in English: digits, either without commas
+// or grouped by three with them, optionally followed by a decimal point and
+// more digits.
+func validCountNumber(s string) bool {
+	integer, fraction, hasFraction := strings.Cut(s, ".")
+	if integer == "" || (hasFraction && (fraction == "" || strings.ContainsAny(fraction, ".,"))) {
+		return false
+	}
+
+	groups := strings.Split(integer, ",")
+	if len(groups) == 1 {
+		return true
+	}
+
+	if len(groups[0]) == 0 || len(groups[0]) > 3 {
+		return false
+	}
+	for _, g := range groups[1:] {
+		if len(g) != 3 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,31 @@
+package xmlapi
+
+import "testing"
+
+func TestParseApproximateCount(t *testing.T) {
+	tests := []struct {
+		s  string
+		n  int64
+		ok bool
+	}{
+		{"1.23M subscribers", 1230000, true},
+		{"950K views", 950000, true},
+		{"1,234 subscribers", 1234, true},
+		{"12,345,678 views", 12345678, true},
+		{"2B", 2000000000, true},
+		{"42", 42, true},
+		{"1,23 Mio. Abonnenten", 0, false},
+		{"1,2 M de suscriptores", 0, false},
+		{"12,34,567 views", 0, false},
+		{"1..2M", 0, false},
+		{"No subscribers", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		n, ok := ParseApproximateCount(tt.s)
+		if n != tt.n || ok != tt.ok {
+			t.Errorf("ParseApproximateCount(%q) = %d, %t; want %d, %t", tt.s, n, ok, tt.n, tt.ok)
+		}
+	}
+}
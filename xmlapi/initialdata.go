@@ -0,0 +1,403 @@
+package xmlapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+//////////////////////////////////////////////////
+
+// Names of the JSON objects embedded in YouTube pages.
+const (
+	InitialData           = "ytInitialData"
+	InitialPlayerResponse = "ytInitialPlayerResponse"
+)
+
+var PageDataNotFound = errors.New("page data not found")
+
+// A JSON object embedded in a YouTube page (e.g., ytInitialData), as decoded
+// by encoding/json (i.e., objects are map[string]any, arrays are []any).
+type PageData map[string]any
+
+// Follows a path of object keys (strings) and array indices (ints; negative
+// ones count from the end) into the data; returns nil if any step is missing.
+func (d PageData) Lookup(path ...any) any {
+	return lookup(map[string]any(d), path...)
+}
+
+func (d PageData) String(path ...any) string {
+	return lookupString(map[string]any(d), path...)
+}
+
+// Returns the text of a "simpleText", "content" or "runs" text object.
+func (d PageData) Text(path ...any) string {
+	return lookupText(map[string]any(d), path...)
+}
+
+func (d PageData) Bool(path ...any) bool {
+	b, _ := d.Lookup(path...).(bool)
+	return b
+}
+
+// Returns a number, which may also be encoded as a string (as is often the
+// case with counts and timestamps).
+func (d PageData) Int(path ...any) (n int64, ok bool) {
+	return lookupInt(map[string]any(d), path...)
+}
+
+//////////////////////////////////////////////////
+
+// Locates the given object (e.g., InitialData) in an HTML page, and decodes
+// it.
+func ExtractPageData(b []byte, name string) (PageData, error) {
+	if len(b) == 0 {
+		return nil, errors.New("b is empty")
+	}
+
+	for p := b; ; {
+		pos := bytes.Index(p, []byte(name))
+		if pos < 0 {
+			return nil, fmt.Errorf("%w: %s", PageDataNotFound, name)
+		}
+		p = p[pos+len(name):]
+
+		if obj, ok := decodeAssignment(p); ok {
+			return PageData(obj), nil
+		}
+	}
+}
+
+// Reads an HTML page, decoding the given objects (e.g., InitialData and
+// InitialPlayerResponse) from its <script> tags, and stops reading once all
+// of them have been found; fails with PageDataNotFound if none of them has
+// been, or with BodyTooLarge if more than limit bytes would have to be read
+// (a limit of 0 or less means no limit).
+func ExtractPageDataReader(r io.Reader, limit int64, names ...string) (found map[string]PageData, err error) {
+	if r == nil {
+		return nil, errors.New("r is nil")
+	}
+	if len(names) == 0 {
+		names = []string{InitialData, InitialPlayerResponse}
+	}
+
+	found = make(map[string]PageData, len(names))
+
+	z := html.NewTokenizer(newLimitedReader(r, limit))
+	for len(found) < len(names) {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err = z.Err(); err != io.EOF {
+				return nil, fmt.Errorf("failed HTML extraction: %w", err)
+			}
+
+			break
+		}
+
+		if tt != html.StartTagToken {
+			continue
+		}
+		if name, _ := z.TagName(); atom.Lookup(name) != atom.Script || z.Next() != html.TextToken {
+			continue
+		}
+
+		script := z.Text()
+		for _, name := range names {
+			if _, ok := found[name]; ok {
+				continue
+			}
+
+			if obj, ok := extractScriptObject(string(script), name); ok {
+				found[name] = PageData(obj)
+			}
+		}
+	}
+
+	if len(found) == 0 {
+		return nil, fmt.Errorf("%w: %s", PageDataNotFound, strings.Join(names, ", "))
+	}
+
+	return found, nil
+}
+
+// Decodes the JSON object assigned to the given variable (e.g.,
+// "ytInitialData") in the contents of a <script> tag.
+func extractScriptObject(script string, name string) (obj map[string]any, ok bool) {
+	for p := script; ; {
+		pos := strings.Index(p, name)
+		if pos < 0 {
+			return nil, false
+		}
+		p = p[pos+len(name):]
+
+		if obj, ok = decodeAssignment([]byte(p)); ok {
+			return
+		}
+	}
+}
+
+// Decodes the value of an assignment following a variable name, i.e., one of:
+//
+//	var ytInitialData = {...};
+//	window["ytInitialData"] = {...};
+//	var ytInitialData = '\x7b...\x7d';
+//
+// (the last one being how mobile pages embed it).
+func decodeAssignment(p []byte) (obj map[string]any, ok bool) {
+	p = bytes.TrimLeft(p, "\"']")
+	p = bytes.TrimLeft(p, " \t\r\n")
+	if len(p) == 0 || p[0] != '=' {
+		return nil, false
+	}
+	p = bytes.TrimLeft(p[1:], " \t\r\n")
+	if len(p) == 0 {
+		return nil, false
+	}
+
+	switch p[0] {
+	case '{':
+		if err := json.NewDecoder(bytes.NewReader(p)).Decode(&obj); err != nil {
+			return nil, false
+		}
+
+	case '\'', '"':
+		s, ok := unquoteScriptString(p)
+		if !ok {
+			return nil, false
+		}
+		if err := json.Unmarshal([]byte(s), &obj); err != nil {
+			return nil, false
+		}
+
+	default:
+		return nil, false
+	}
+
+	return obj, obj != nil
+}
+
+// Unquotes a JavaScript string literal at the beginning of p (supporting the
+// escape sequences used by YouTube: \xHH, \uHHHH and single characters).
+func unquoteScriptString(p []byte) (s string, ok bool) {
+	quote := p[0]
+
+	var b strings.Builder
+	for i := 1; i < len(p); i++ {
+		c := p[i]
+
+		switch {
+		case c == quote:
+			return b.String(), true
+
+		case c != '\\':
+			b.WriteByte(c)
+
+		case i+1 < len(p):
+			i++
+
+			switch e := p[i]; e {
+			case 'x', 'u':
+				n := 2
+				if e == 'u' {
+					n = 4
+				}
+				if i+n >= len(p) {
+					return "", false
+				}
+
+				v, err := strconv.ParseUint(string(p[i+1:i+1+n]), 16, 32)
+				if err != nil {
+					return "", false
+				}
+				r := rune(v)
+				i += n
+
+				// NOTE: characters outside of the BMP are escaped as UTF-16
+				// surrogate pairs (e.g., "\uD83D\uDE00").
+				if e == 'u' && utf16.IsSurrogate(r) && i+6 < len(p) && p[i+1] == '\\' && p[i+2] == 'u' {
+					if v, err := strconv.ParseUint(string(p[i+3:i+7]), 16, 32); err == nil {
+						if pr := utf16.DecodeRune(r, rune(v)); pr != utf8.RuneError {
+							r = pr
+							i += 6
+						}
+					}
+				}
+				b.WriteRune(r)
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				b.WriteByte(e)
+			}
+
+		default:
+			return "", false
+		}
+	}
+
+	return "", false
+}
+
+//////////////////////////////////////////////////
+
+func lookup(v any, path ...any) any {
+	for _, step := range path {
+		switch step := step.(type) {
+		case string:
+			m, ok := v.(map[string]any)
+			if !ok {
+				return nil
+			}
+			v = m[step]
+
+		case int:
+			a, ok := v.([]any)
+			if !ok {
+				return nil
+			}
+			if step < 0 {
+				step += len(a)
+			}
+			if step < 0 || step >= len(a) {
+				return nil
+			}
+			v = a[step]
+
+		default:
+			return nil
+		}
+	}
+
+	return v
+}
+
+func lookupString(v any, path ...any) string {
+	s, _ := lookup(v, path...).(string)
+	return s
+}
+
+func lookupInt(v any, path ...any) (n int64, ok bool) {
+	switch v := lookup(v, path...).(type) {
+	case float64:
+		return int64(v), true
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		return n, err == nil
+	}
+
+	return 0, false
+}
+
+func lookupText(v any, path ...any) string {
+	v = lookup(v, path...)
+	if s := lookupString(v, "simpleText"); s != "" {
+		return s
+	}
+	if s := lookupString(v, "content"); s != "" {
+		return s
+	}
+
+	runs, _ := lookup(v, "runs").([]any)
+
+	var s strings.Builder
+	for _, run := range runs {
+		s.WriteString(lookupString(run, "text"))
+	}
+
+	return s.String()
+}
+
+// Returns the URL of the last (usually largest) image in a "thumbnails" or
+// "sources" list.
+func lookupImageURL(v any, path ...any) string {
+	v = lookup(v, path...)
+
+	for _, key := range []string{"thumbnails", "sources"} {
+		if u := lookupString(v, key, -1, "url"); u != "" {
+			if strings.HasPrefix(u, "//") {
+				u = "https:" + u
+			}
+
+			return u
+		}
+	}
+
+	return ""
+}
+
+// Calls fn for every string within a decoded JSON value (depth-first), until
+// fn returns false.
+func walkStrings(v any, fn func(s string) bool) bool {
+	switch v := v.(type) {
+	case string:
+		return fn(v)
+
+	case map[string]any:
+		for _, vv := range v {
+			if !walkStrings(vv, fn) {
+				return false
+			}
+		}
+
+	case []any:
+		for _, vv := range v {
+			if !walkStrings(vv, fn) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Calls fn for every object within a decoded JSON value (depth-first, in
+// document order of arrays and in key order of objects) that has one of the
+// given keys, with the key and its value, until fn returns false.
+func walkKeys(v any, keys []string, fn func(key string, v any) bool) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		for _, key := range keys {
+			if vv, ok := v[key]; ok {
+				if !fn(key, vv) {
+					return false
+				}
+			}
+		}
+
+		ks := make([]string, 0, len(v))
+		for k := range v {
+			if !slices.Contains(keys, k) {
+				ks = append(ks, k)
+			}
+		}
+		slices.Sort(ks)
+
+		for _, k := range ks {
+			if !walkKeys(v[k], keys, fn) {
+				return false
+			}
+		}
+
+	case []any:
+		for _, vv := range v {
+			if !walkKeys(vv, keys, fn) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
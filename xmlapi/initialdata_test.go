@@ -0,0 +1,113 @@
+package xmlapi
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestExtractPageData(t *testing.T) {
+	tests := []struct {
+		file string
+		name string
+		// Path to a string value expected in the data, and that value.
+		path []any
+		want string
+	}{
+		{"watch_live.html", InitialPlayerResponse, []any{"videoDetails", "videoId"}, "jfKfPfyJRdk"},
+		{"watch_live.html", InitialData, []any{"contents", "twoColumnWatchNextResults"}, ""},
+		{"streams_window.html", InitialData, []any{"contents", "twoColumnBrowseResultsRenderer", "tabs", 0, "tabRenderer", "title"}, "Live"},
+		{"streams_mobile.html", InitialData, []any{"contents", "twoColumnBrowseResultsRenderer", "tabs", 0, "tabRenderer", "title"}, "Live"},
+	}
+
+	for _, tt := range tests {
+		b := readTestdata(t, tt.file)
+
+		d, err := ExtractPageData(b, tt.name)
+		if err != nil {
+			t.Fatalf("%s: ExtractPageData(%s): %v", tt.file, tt.name, err)
+		}
+		if d.Lookup(tt.path...) == nil || d.String(tt.path...) != tt.want {
+			t.Errorf("%s: ExtractPageData(%s): got %v at %v, want %q", tt.file, tt.name, d.Lookup(tt.path...), tt.path, tt.want)
+		}
+
+		found, err := ExtractPageDataReader(strings.NewReader(string(b)), 0, tt.name)
+		if err != nil {
+			t.Fatalf("%s: ExtractPageDataReader(%s): %v", tt.file, tt.name, err)
+		}
+		if found[tt.name].Lookup(tt.path...) == nil || found[tt.name].String(tt.path...) != tt.want {
+			t.Errorf("%s: ExtractPageDataReader(%s): got %v at %v, want %q", tt.file, tt.name, found[tt.name].Lookup(tt.path...), tt.path, tt.want)
+		}
+	}
+}
+
+func TestExtractPageDataNotFound(t *testing.T) {
+	b := readTestdata(t, "no_data.html")
+
+	if _, err := ExtractPageData(b, InitialData); !errors.Is(err, PageDataNotFound) {
+		t.Errorf("ExtractPageData: got %v, want PageDataNotFound", err)
+	}
+	if _, err := ExtractPageDataReader(strings.NewReader(string(b)), 0); !errors.Is(err, PageDataNotFound) {
+		t.Errorf("ExtractPageDataReader: got %v, want PageDataNotFound", err)
+	}
+}
+
+func TestExtractPageDataReaderLimit(t *testing.T) {
+	b := readTestdata(t, "streams_window.html")
+
+	if _, err := ExtractPageDataReader(strings.NewReader(string(b)), 256); !errors.Is(err, BodyTooLarge) {
+		t.Errorf("got %v, want BodyTooLarge", err)
+	}
+}
+
+func TestExtractPageDataReaderBoth(t *testing.T) {
+	found, err := ExtractPageDataReader(strings.NewReader(string(readTestdata(t, "watch_live.html"))), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := found[InitialData]; !ok {
+		t.Errorf("%s not found", InitialData)
+	}
+	if _, ok := found[InitialPlayerResponse]; !ok {
+		t.Errorf("%s not found", InitialPlayerResponse)
+	}
+}
+
+func TestUnquoteScriptString(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+		ok   bool
+	}{
+		{`'\x7b"a":1\x7d'`, `{"a":1}`, true},
+		{`"line\nbreak"`, "line\nbreak", true},
+		{`'caf\u00e9'`, "caf\u00e9", true},
+		{`'\uD83D\uDE00!'`, "\U0001F600!", true},
+		{`'\ud83d\ude00'`, "\U0001F600", true},
+		{`'\uD83D.'`, "\uFFFD.", true},
+		{`'\uDE00\uD83D'`, "\uFFFD\uFFFD", true},
+		{`'\x7`, "", false},
+		{`'unterminated`, "", false},
+	}
+
+	for _, tt := range tests {
+		s, ok := unquoteScriptString([]byte(tt.src))
+		if s != tt.want || ok != tt.ok {
+			t.Errorf("unquoteScriptString(%s) = %q, %t; want %q, %t", tt.src, s, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package xmlapi
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+//////////////////////////////////////////////////
+
+// Live-related details of a video, from the ytInitialPlayerResponse of its
+// watch page.
+type PlayerLiveDetails struct {
+	VideoID   string `json:"video_id"`
+	ChannelID string `json:"channel_id"`
+	Title     string `json:"title"`
+
+	// Whether the video is (or was, or will be) a livestream or a premiere.
+	LiveContent bool `json:"live_content"`
+	Live        bool `json:"live"`
+	Upcoming    bool `json:"upcoming"`
+
+	// e.g., "OK", "LIVE_STREAM_OFFLINE", "UNPLAYABLE" or "LOGIN_REQUIRED".
+	PlayabilityStatus string `json:"playability_status"`
+
+	ScheduledStartTime int64 `json:"scheduled_start_time"` // (unix timestamp; in milliseconds)
+	ActualStartTime    int64 `json:"actual_start_time"`    // (unix timestamp; in milliseconds)
+	ActualEndTime      int64 `json:"actual_end_time"`      // (unix timestamp; in milliseconds)
+
+	ViewCount int64 `json:"view_count"`
+}
+
+func (det PlayerLiveDetails) String() string {
+	var s strings.Builder
+
+	s.WriteString("{PlayerLiveDetails:[videoID:")
+	s.WriteString(strconv.Quote(det.VideoID))
+	s.WriteString(", live:")
+	s.WriteString(strconv.FormatBool(det.Live))
+	s.WriteString(", upcoming:")
+	s.WriteString(strconv.FormatBool(det.Upcoming))
+	s.WriteString("]}")
+
+	return s.String()
+}
+
+// Returns the live-related details of a video, provided that d is a
+// ytInitialPlayerResponse.
+func (d PageData) PlayerLiveDetails() (det PlayerLiveDetails) {
+	vd := d.Lookup("videoDetails")
+
+	det.VideoID = lookupString(vd, "videoId")
+	det.ChannelID = lookupString(vd, "channelId")
+	det.Title = lookupString(vd, "title")
+
+	det.LiveContent, _ = lookup(vd, "isLiveContent").(bool)
+	det.Live, _ = lookup(vd, "isLive").(bool)
+	det.Upcoming, _ = lookup(vd, "isUpcoming").(bool)
+	det.ViewCount, _ = lookupInt(vd, "viewCount")
+
+	det.PlayabilityStatus = d.String("playabilityStatus", "status")
+
+	lbd := d.Lookup("microformat", "playerMicroformatRenderer", "liveBroadcastDetails")
+	if lbd != nil {
+		if liveNow, ok := lookup(lbd, "isLiveNow").(bool); ok && liveNow {
+			det.Live = true
+		}
+
+		det.ActualStartTime, _ = parseDate(lookupString(lbd, "startTimestamp"))
+		det.ActualEndTime, _ = parseDate(lookupString(lbd, "endTimestamp"))
+	}
+
+	if s, ok := d.Int("playabilityStatus", "liveStreamability", "liveStreamabilityRenderer", "offlineSlate", "liveStreamOfflineSlateRenderer", "scheduledStartTime"); ok {
+		det.ScheduledStartTime = s * int64(time.Second/time.Millisecond)
+	}
+
+	if det.ActualEndTime != 0 {
+		det.Live = false
+		det.Upcoming = false
+	}
+
+	// NOTE: until an upcoming video starts, startTimestamp is its scheduled
+	// start time.
+	if det.Upcoming && !det.Live {
+		if det.ScheduledStartTime == 0 {
+			det.ScheduledStartTime = det.ActualStartTime
+		}
+		det.ActualStartTime = 0
+	}
+
+	return
+}
+
+//////////////////////////////////////////////////
+
+// A video listed on a page (e.g., a channel's "Live" tab), as found in its
+// ytInitialData.
+type PageVideo struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+
+	// Set if the video carries a "LIVE NOW" badge (or a "LIVE" overlay).
+	Live bool `json:"live"`
+	// Set if the video is a scheduled livestream (or premiere).
+	Upcoming           bool  `json:"upcoming"`
+	ScheduledStartTime int64 `json:"scheduled_start_time"` // (unix timestamp; in milliseconds)
+
+	// As displayed (e.g., "1.2K watching" or "3,456 views"), and the
+	// (approximate) number of concurrent viewers, if live.
+	ViewCountText string `json:"view_count_text"`
+	Viewers       int64  `json:"viewers"`
+
+	// As displayed (e.g., "Streamed 2 days ago").
+	PublishedTimeText string `json:"published_time_text"`
+}
+
+func (vid PageVideo) String() string {
+	var s strings.Builder
+
+	s.WriteString("{PageVideo:[id:")
+	s.WriteString(strconv.Quote(vid.ID))
+	s.WriteString(", live:")
+	s.WriteString(strconv.FormatBool(vid.Live))
+	s.WriteString("]}")
+
+	return s.String()
+}
+
+var videoRendererKeys = []string{
+	"videoRenderer",
+	"gridVideoRenderer",
+	"compactVideoRenderer",
+}
+
+// Returns the videos listed in d (a ytInitialData), in page order and
+// without duplicates.
+func (d PageData) Videos() (videos []PageVideo) {
+	videos = make([]PageVideo, 0)
+	seen := make(map[string]struct{})
+
+	walkKeys(map[string]any(d), videoRendererKeys, func(_ string, v any) bool {
+		vid, ok := parseVideoRenderer(v)
+		if !ok {
+			return true
+		}
+		if _, dup := seen[vid.ID]; dup {
+			return true
+		}
+
+		seen[vid.ID] = struct{}{}
+		videos = append(videos, vid)
+
+		return true
+	})
+
+	return
+}
+
+// Returns the videos in d (a ytInitialData) which are live now.
+func (d PageData) LiveVideos() (videos []PageVideo) {
+	videos = make([]PageVideo, 0)
+	for _, vid := range d.Videos() {
+		if vid.Live {
+			videos = append(videos, vid)
+		}
+	}
+
+	return
+}
+
+func parseVideoRenderer(r any) (vid PageVideo, ok bool) {
+	vid.ID = lookupString(r, "videoId")
+	if !IsValidVideoID(vid.ID) {
+		return vid, false
+	}
+
+	vid.Title = lookupText(r, "title")
+	vid.ViewCountText = lookupText(r, "viewCountText")
+	if vid.ViewCountText == "" {
+		vid.ViewCountText = lookupText(r, "shortViewCountText")
+	}
+	vid.PublishedTimeText = lookupText(r, "publishedTimeText")
+
+	badges, _ := lookup(r, "badges").([]any)
+	for _, badge := range badges {
+		if lookupString(badge, "metadataBadgeRenderer", "style") == "BADGE_STYLE_TYPE_LIVE_NOW" {
+			vid.Live = true
+		}
+	}
+
+	overlays, _ := lookup(r, "thumbnailOverlays").([]any)
+	for _, overlay := range overlays {
+		switch lookupString(overlay, "thumbnailOverlayTimeStatusRenderer", "style") {
+		case "LIVE":
+			vid.Live = true
+		case "UPCOMING":
+			vid.Upcoming = true
+		}
+	}
+
+	if startTime, ok := lookupInt(r, "upcomingEventData", "startTime"); ok {
+		vid.Upcoming = !vid.Live
+		vid.ScheduledStartTime = startTime * int64(time.Second/time.Millisecond)
+	}
+
+	if vid.Live {
+		vid.Viewers, _ = ParseApproximateCount(vid.ViewCountText)
+	}
+
+	return vid, true
+}
@@ -0,0 +1,115 @@
+package xmlapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func mustExtractPageData(t *testing.T, file string, name string) PageData {
+	t.Helper()
+
+	d, err := ExtractPageData(readTestdata(t, file), name)
+	if err != nil {
+		t.Fatalf("%s: %v", file, err)
+	}
+
+	return d
+}
+
+func unixMilli(s string) int64 {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return t.UnixMilli()
+}
+
+func TestPlayerLiveDetails(t *testing.T) {
+	tests := []struct {
+		file string
+		want PlayerLiveDetails
+	}{
+		{"watch_live.html", PlayerLiveDetails{
+			VideoID:           "jfKfPfyJRdk",
+			ChannelID:         "UCSJ4gkVC6NrvII8umztf0Ow",
+			Title:             "lofi hip hop radio 📚 beats to relax/study to",
+			LiveContent:       true,
+			Live:              true,
+			PlayabilityStatus: "OK",
+			ActualStartTime:   unixMilli("2026-01-05T12:00:00Z"),
+			ViewCount:         23456,
+		}},
+		{"watch_upcoming.html", PlayerLiveDetails{
+			VideoID:            "aaaaaaaaaaa",
+			ChannelID:          "UCSJ4gkVC6NrvII8umztf0Ow",
+			Title:              "Upcoming stream",
+			LiveContent:        true,
+			Upcoming:           true,
+			PlayabilityStatus:  "LIVE_STREAM_OFFLINE",
+			ScheduledStartTime: unixMilli("2026-01-05T14:00:00Z"),
+		}},
+		{"watch_ended.html", PlayerLiveDetails{
+			VideoID:           "bbbbbbbbbbb",
+			ChannelID:         "UCSJ4gkVC6NrvII8umztf0Ow",
+			Title:             "Past stream",
+			LiveContent:       true,
+			PlayabilityStatus: "OK",
+			ActualStartTime:   unixMilli("2026-01-04T12:00:00Z"),
+			ActualEndTime:     unixMilli("2026-01-04T14:00:00Z"),
+			ViewCount:         1000,
+		}},
+	}
+
+	for _, tt := range tests {
+		got := mustExtractPageData(t, tt.file, InitialPlayerResponse).PlayerLiveDetails()
+		if got != tt.want {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestPageDataVideos(t *testing.T) {
+	live := PageVideo{
+		ID:            "jfKfPfyJRdk",
+		Title:         "lofi hip hop radio",
+		Live:          true,
+		ViewCountText: "23,456 watching",
+		Viewers:       23456,
+	}
+	upcoming := PageVideo{
+		ID:                 "aaaaaaaaaaa",
+		Title:              "Upcoming stream",
+		Upcoming:           true,
+		ScheduledStartTime: unixMilli("2026-01-05T14:00:00Z"),
+	}
+	past := PageVideo{
+		ID:                "bbbbbbbbbbb",
+		Title:             "Past stream",
+		ViewCountText:     "1,000 views",
+		PublishedTimeText: "Streamed 1 day ago",
+	}
+
+	tests := []struct {
+		file       string
+		videos     []PageVideo
+		liveVideos []PageVideo
+	}{
+		// NOTE: the live video is listed twice (the duplicate being left out).
+		{"streams_window.html", []PageVideo{live, upcoming, past}, []PageVideo{live}},
+		{"streams_mobile.html", []PageVideo{live, upcoming}, []PageVideo{live}},
+		{"watch_live.html", []PageVideo{}, []PageVideo{}},
+	}
+
+	for _, tt := range tests {
+		d := mustExtractPageData(t, tt.file, InitialData)
+
+		if got := d.Videos(); !reflect.DeepEqual(got, tt.videos) {
+			t.Errorf("%s: Videos:\ngot  %+v\nwant %+v", tt.file, got, tt.videos)
+		}
+		if got := d.LiveVideos(); !reflect.DeepEqual(got, tt.liveVideos) {
+			t.Errorf("%s: LiveVideos:\ngot  %+v\nwant %+v", tt.file, got, tt.liveVideos)
+		}
+	}
+}
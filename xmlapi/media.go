@@ -5,10 +5,10 @@ import "encoding/xml"
 //////////////////////////////////////////////////
 
 type MediaContent struct {
-	URL    string `xml:"url,attr"`
-	Type   string `xml:"type,attr"`
-	Width  int    `xml:"width,attr"`
-	Height int    `xml:"height,attr"`
+	URL    string `xml:"url,attr" json:"url"`
+	Type   string `xml:"type,attr" json:"type"`
+	Width  int    `xml:"width,attr" json:"width"`
+	Height int    `xml:"height,attr" json:"height"`
 }
 
 type MediaThumbnail struct {
@@ -18,14 +18,14 @@ type MediaThumbnail struct {
 }
 
 type MediaStatistics struct {
-	Views int `xml:"views,attr"`
+	Views int64 `xml:"views,attr"`
 }
 
 type MediaStarRating struct {
-	Count   int     `xml:"count,attr"`
-	Average float32 `xml:"average,attr"`
-	Min     int     `xml:"min,attr"`
-	Max     int     `xml:"max,attr"`
+	Count   int     `xml:"count,attr" json:"count"`
+	Average float32 `xml:"average,attr" json:"average"`
+	Min     int     `xml:"min,attr" json:"min"`
+	Max     int     `xml:"max,attr" json:"max"`
 }
 
 type MediaCommunity struct {
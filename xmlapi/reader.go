@@ -0,0 +1,52 @@
+package xmlapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+//////////////////////////////////////////////////
+
+var BodyTooLarge = errors.New("body exceeds size limit")
+
+// Default byte cap of the Parse*Reader functions.
+const DefaultMaximumBodySize int64 = 8 << 20
+
+// Reads from R, failing with BodyTooLarge once more than Limit bytes have
+// been read (a Limit of 0 or less means no limit).
+type limitedReader struct {
+	R     io.Reader
+	Limit int64
+
+	n int64
+}
+
+func newLimitedReader(r io.Reader, limit int64) io.Reader {
+	if limit <= 0 {
+		return r
+	}
+
+	return &limitedReader{R: r, Limit: limit}
+}
+
+func (l *limitedReader) Read(p []byte) (n int, err error) {
+	if l.n > l.Limit {
+		return 0, fmt.Errorf("%w (%d bytes)", BodyTooLarge, l.Limit)
+	}
+
+	// NOTE: reads (at most) one byte past the limit, to tell a body of
+	// exactly Limit bytes apart from a larger one.
+	if remaining := l.Limit - l.n + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err = l.R.Read(p)
+	l.n += int64(n)
+	if l.n > l.Limit {
+		n -= int(l.n - l.Limit)
+		err = fmt.Errorf("%w (%d bytes)", BodyTooLarge, l.Limit)
+	}
+
+	return
+}
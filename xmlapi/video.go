@@ -1,5 +1,7 @@
 package xmlapi
 
+import "strings"
+
 //////////////////////////////////////////////////
 
 // Checks (roughly) if the given string is a valid YouTube video ID.
@@ -21,3 +23,38 @@ func IsValidVideoID(s string) bool {
 
 	return true
 }
+
+// Prefixes of playlist IDs (e.g., "PL" for user playlists, "UU" for the
+// uploads of a channel).
+var playlistIDPrefixes = []string{"PL", "UU", "LL", "FL", "OL", "RD"}
+
+// Checks (roughly) if the given string is a valid YouTube playlist ID.
+func IsValidPlaylistID(s string) bool {
+	n := len(s)
+	if n < 12 || n > 64 {
+		return false
+	}
+
+	prefixed := false
+	for _, prefix := range playlistIDPrefixes {
+		if strings.HasPrefix(s, prefix) {
+			prefixed = true
+			break
+		}
+	}
+	if !prefixed {
+		return false
+	}
+
+	for _, r := range s {
+		if !((r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')) {
+			if r == '-' || r == '_' {
+				continue
+			}
+
+			return false
+		}
+	}
+
+	return true
+}
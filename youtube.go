@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"net/url"
 	"strings"
 	"time"
@@ -37,13 +36,68 @@ func (cr *Crawler) canonicalHandle(handle Handle) Handle {
 	return handle
 }
 
+// Returns the handle under which the crawler tracks the entity (i.e., a
+// channel URL is replaced with its channel ID, provided it has already been
+// resolved).
+func (cr *Crawler) CanonicalHandle(handle Handle) Handle {
+	return cr.canonicalHandle(handle)
+}
+
 func (cr *Crawler) IsTracked(handle Handle) bool {
 	return cr.Crawler.IsTracked(cr.canonicalHandle(handle))
 }
 
+func (cr *Crawler) Track(ctx context.Context, handle Handle) (tracked bool, err error) {
+	return cr.Crawler.Track(ctx, cr.canonicalHandle(handle))
+}
+
+func (cr *Crawler) Untrack(ctx context.Context, handle Handle) (tracked bool, err error) {
+	handle = cr.canonicalHandle(handle)
+	cr.forget(handle)
+
+	return cr.Crawler.Untrack(ctx, handle)
+}
+
+// Discards the state kept by the crawler for a handle (outside of its entity
+// data), once it is no longer tracked.
+func (cr *Crawler) forget(handle Handle) {
+	cr.overrides.Delete(handle)
+	cr.failureResets.Delete(handle)
+	if handle.Type == HandleChannelID {
+		cr.channelInfoFailures.Delete(handle.Value)
+	}
+}
+
 //////////////////////////////////////////////////
 
+// Live streaming details of a single video, as reported by the YouTube Data
+// API (timestamps are zero if unknown).
+type LiveVideoState struct {
+	Live     bool `json:"live"`
+	Finished bool `json:"finished"`
+
+	ScheduledStartTime time.Time `json:"scheduled_start_time"`
+	ActualStartTime    time.Time `json:"actual_start_time"`
+	ActualEndTime      time.Time `json:"actual_end_time"`
+	ConcurrentViewers  uint64    `json:"concurrent_viewers"`
+
+	// Set if the video is a premiere (i.e., a pre-recorded video played back
+	// "live"), which is told apart from a livestream by having a duration
+	// before it has ended (that of an unfinished livestream is zero).
+	Premiere bool          `json:"premiere"`
+	Duration time.Duration `json:"duration"`
+
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
 func (cr *Crawler) CheckLiveVideoState(ctx context.Context, videoID string) (live bool, finished bool, err error) {
+	state, err := cr.CheckLiveVideoDetails(ctx, videoID)
+
+	return state.Live, state.Finished, err
+}
+
+func (cr *Crawler) CheckLiveVideoDetails(ctx context.Context, videoID string) (state LiveVideoState, err error) {
 	if videoID == "" || !IsValidVideoID(videoID) {
 		err = InvalidVideoID
 		return
@@ -62,11 +116,13 @@ func (cr *Crawler) CheckLiveVideoState(ctx context.Context, videoID string) (liv
 		}
 	}
 
-	part := []string{"liveStreamingDetails"}
+	// NOTE: parts do not affect the quota cost of a call.
+	part := []string{"liveStreamingDetails", "contentDetails", "snippet"}
 	call := cr.service.Videos.List(part)
 	call.Context(ctx)
 	call.Id(videoID)
 
+	cr.recordQuota("videos.list", quotaCostList)
 	resp, err := call.Do()
 	if err != nil {
 		err = fmt.Errorf("youtube.VideosService.List: %w", err)
@@ -80,13 +136,28 @@ func (cr *Crawler) CheckLiveVideoState(ctx context.Context, videoID string) (liv
 		d := item.LiveStreamingDetails
 
 		if d.ActualEndTime != "" {
-			finished = true
+			state.Finished = true
 		} else {
 			if d.ActualStartTime != "" {
-				live = true
+				state.Live = true
 			}
 		}
 
+		state.ScheduledStartTime, _ = parseAPITime(d.ScheduledStartTime)
+		state.ActualStartTime, _ = parseAPITime(d.ActualStartTime)
+		state.ActualEndTime, _ = parseAPITime(d.ActualEndTime)
+		state.ConcurrentViewers = d.ConcurrentViewers
+
+		if item.ContentDetails != nil {
+			state.Duration, _ = parseAPIDuration(item.ContentDetails.Duration)
+		}
+		state.Premiere = !state.Finished && state.Duration > 0
+
+		if item.Snippet != nil {
+			state.Title = item.Snippet.Title
+			state.Description = item.Snippet.Description
+		}
+
 		return
 	}
 
@@ -99,6 +170,21 @@ func (cr *Crawler) FetchChannelXMLFeed(ctx context.Context, channelID string) (f
 		return
 	}
 
+	return cr.fetchXMLFeed(ctx, "channel_id", channelID)
+}
+
+// Fetches the feed of a playlist, which has the same format as that of a
+// channel (except that each entry's ChannelID is that of the video's owner).
+func (cr *Crawler) FetchPlaylistXMLFeed(ctx context.Context, playlistID string) (feed *xmlapi.ChannelFeed, err error) {
+	if playlistID == "" || !IsValidPlaylistID(playlistID) {
+		err = InvalidPlaylistID
+		return
+	}
+
+	return cr.fetchXMLFeed(ctx, "playlist_id", playlistID)
+}
+
+func (cr *Crawler) fetchXMLFeed(ctx context.Context, key string, value string) (feed *xmlapi.ChannelFeed, err error) {
 	if cr.client == nil {
 		err = NilClient
 		return
@@ -118,34 +204,53 @@ func (cr *Crawler) FetchChannelXMLFeed(ctx context.Context, channelID string) (f
 		Path:   "feeds/videos.xml",
 	}
 	q := feedURL.Query()
-	q.Set("channel_id", channelID)
-	q.Set(nonceKey, generateNonce())
+	q.Set(key, value)
+	q.Set(nonceKey, generateNonce(cr.now()))
 	feedURL.RawQuery = q.Encode()
 
+	profile := cr.loadRequestProfile()
+	profile.applyQuery(EndpointChannelFeed, feedURL)
+
 	rawFeedURL := feedURL.String()
 
-	resp, err := cr.client.Request(ctx, "GET", rawFeedURL, nil, http.Header{
-		"Cookie": {generateConsentCookie()},
-	})
+	resp, err := cr.request(ctx, EndpointChannelFeed, rawFeedURL, profile.header(EndpointChannelFeed, cr.now()))
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	limit := cr.loadEffectiveSettings().MaximumResponseBodySize
+
+	if resp.StatusCode != 200 {
+		body, err := readBody(resp.Body, limit)
+		if err != nil {
+			return nil, newFetchError(EndpointChannelFeed, rawFeedURL, resp.StatusCode, body, err)
+		}
+
+		return nil, cr.responseError(ctx, EndpointChannelFeed, rawFeedURL, resp, body, UnexpectedStatus)
 	}
 
-	feed, err = xmlapi.ParseChannelFeed(body)
+	captured := newCapturedBody()
+	feed, err = xmlapi.ParseChannelFeedReader(io.TeeReader(resp.Body, captured), limit)
 	if err != nil {
-		return nil, err
+		return nil, cr.responseError(ctx, EndpointChannelFeed, rawFeedURL, resp, captured.Bytes(), err)
 	}
+	cr.recordSuccess(ctx, EndpointChannelFeed)
 
 	return feed, nil
 }
 
 func (cr *Crawler) FetchChannelIndex(ctx context.Context, channelURL string) (index *xmlapi.ChannelIndex, err error) {
+	return cr.fetchChannelIndex(ctx, channelURL, "")
+}
+
+// Language (of the "hl" param) in which counts found on YouTube pages can be
+// parsed.
+const countLanguage = "en"
+
+// Same as FetchChannelIndex, but with the page requested in the given
+// language (if non-empty) instead of that of the request profile.
+func (cr *Crawler) fetchChannelIndex(ctx context.Context, channelURL string, language string) (index *xmlapi.ChannelIndex, err error) {
 	if channelURL == "" || !IsValidChannelURL(channelURL) {
 		err = InvalidChannelURL
 		return
@@ -164,23 +269,49 @@ func (cr *Crawler) FetchChannelIndex(ctx context.Context, channelURL string) (in
 		}
 	}
 
-	resp, err := cr.client.Request(ctx, "GET", channelURL, nil, http.Header{
-		"Cookie": {generateConsentCookie()},
-	})
+	indexURL, err := url.Parse(channelURL)
 	if err != nil {
-		return nil, err
+		err = InvalidChannelURL
+		return
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	profile := cr.loadRequestProfile()
+	if language != "" {
+		profile.Language = language
+	}
+	profile.applyQuery(EndpointChannelIndex, indexURL)
+
+	rawIndexURL := indexURL.String()
+
+	resp, err := cr.request(ctx, EndpointChannelIndex, rawIndexURL, profile.header(EndpointChannelIndex, cr.now()))
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
+
+	limit := cr.loadEffectiveSettings().MaximumResponseBodySize
+
+	if resp.StatusCode != 200 {
+		body, err := readBody(resp.Body, limit)
+		if err != nil {
+			return nil, newFetchError(EndpointChannelIndex, rawIndexURL, resp.StatusCode, body, err)
+		}
+
+		cause := UnexpectedStatus
+		if isTerminatedPage(body) {
+			cause = ChannelTerminated
+		}
+
+		return nil, cr.responseError(ctx, EndpointChannelIndex, rawIndexURL, resp, body, cause)
+	}
 
-	index, err = xmlapi.ParseChannelIndex(body)
+	captured := newCapturedBody()
+	index, err = xmlapi.ParseChannelPageReader(io.TeeReader(resp.Body, captured), limit)
 	if err != nil {
-		return nil, err
+		return nil, cr.responseError(ctx, EndpointChannelIndex, rawIndexURL, resp, captured.Bytes(), err)
 	}
+	cr.storeChannelInfo(index, profile.primaryLanguage() == countLanguage)
+	cr.recordSuccess(ctx, EndpointChannelIndex)
 
 	return index, nil
 }
@@ -256,14 +387,15 @@ func (cr *Crawler) CheckLiveVideoThumbnail(ctx context.Context, videoID string,
 	}
 
 	q := u.Query()
-	q.Set(nonceKey, generateNonce())
+	q.Set(nonceKey, generateNonce(cr.now()))
 	u.RawQuery = q.Encode()
 
+	profile := cr.loadRequestProfile()
+	profile.applyQuery(EndpointThumbnail, u)
+
 	thumbnailURL = u.String()
 
-	resp, err := cr.client.Request(ctx, "GET", thumbnailURL, nil, http.Header{
-		"Cookie": {generateConsentCookie()},
-	})
+	resp, err := cr.request(ctx, EndpointThumbnail, thumbnailURL, profile.header(EndpointThumbnail, cr.now()))
 	if err != nil {
 		return
 	}
@@ -271,13 +403,17 @@ func (cr *Crawler) CheckLiveVideoThumbnail(ctx context.Context, videoID string,
 
 	switch resp.StatusCode {
 	case 404:
+		cr.recordSuccess(ctx, EndpointThumbnail)
 		return false, nil
 
 	case 200, 302, 304:
+		cr.recordSuccess(ctx, EndpointThumbnail)
 		return true, nil
 	}
 
-	return false, UncertainLiveVideoThumbnail
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, int64(maximumBodySnippetLength)))
+
+	return false, newFetchError(EndpointThumbnail, thumbnailURL, resp.StatusCode, body, UncertainLiveVideoThumbnail)
 }
 
 //////////////////////////////////////////////////
@@ -287,8 +423,14 @@ type VideoCandidate struct {
 	ChannelID   string    `json:"channel_id"`
 	LastProcess time.Time `json:"last_process"`
 
-	Live             bool      `json:"live"`
-	LiveGenuine      bool      `json:"live_genuine"`
+	// As listed by the candidate's source (if known).
+	Title        string `json:"title,omitempty"`
+	Description  string `json:"description,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+
+	Live        bool `json:"live"`
+	LiveGenuine bool `json:"live_genuine"`
+	// Deprecated: use FailureState, which covers every processing step.
 	LiveCheckAttempt int       `json:"live_check_attempt"`
 	LastLive         time.Time `json:"last_live"`
 
@@ -297,6 +439,15 @@ type VideoCandidate struct {
 
 	NotLivestream     bool      `json:"not_livestream"`
 	LastNotLivestream time.Time `json:"last_not_livestream"`
+
+	ActualStartTime   time.Time `json:"actual_start_time"`
+	ConcurrentViewers uint64    `json:"concurrent_viewers,omitempty"`
+	Premiere          bool      `json:"premiere,omitempty"`
+	// Known only for upcoming streams (e.g., those found on the channel's
+	// "Live" tab, or once checked via the Data API).
+	ScheduledStartTime time.Time `json:"scheduled_start_time"`
+
+	FailureState
 }
 
 var (
@@ -309,6 +460,7 @@ var (
 	InvalidChannelID            = errors.New("invalid channel ID")
 	InvalidChannelURL           = errors.New("invalid channel URL")
 	InvalidVideoID              = errors.New("invalid video ID")
+	InvalidPlaylistID           = errors.New("invalid playlist ID")
 	InvalidVideoThumbnailURL    = errors.New("invalid video thumbnail URL")
 	UncertainLiveVideoThumbnail = errors.New("uncertain live video thumbnail status")
 )
@@ -316,12 +468,9 @@ var (
 func IsValidChannelID(s string) bool  { return xmlapi.IsValidChannelID(s) }
 func IsValidChannelURL(s string) bool { return isValidURL(s) }
 func IsValidVideoID(s string) bool    { return xmlapi.IsValidVideoID(s) }
+func IsValidPlaylistID(s string) bool { return xmlapi.IsValidPlaylistID(s) }
 
 var (
 	validVideoThumbnailSuffixes      = []string{".jpg", ".webp"}
 	liveVideoThumbnailFilenameSuffix = "_live"
 )
-
-func generateConsentCookie() string {
-	return "SOCS=CAESEwgDEgk0ODE3Nzk3MjQaAmVuIAEaBgiA_LyaBg"
-}
@@ -0,0 +1,151 @@
+package youtubetest
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	fhttp "github.com/bogdanfinn/fhttp"
+	tlsclient "github.com/bogdanfinn/tls-client"
+
+	"github.com/rubpy/crawly/cclient"
+	"github.com/rubpy/crawly/clog"
+)
+
+//////////////////////////////////////////////////
+
+// cclient.Client which sends every request (regardless of its host) to the
+// fake server.
+type Client struct {
+	srv    *Server
+	client *http.Client
+	logger *slog.Logger
+
+	defaultHeader http.Header
+}
+
+var _ cclient.Client = (*Client)(nil)
+
+// Returns a cclient.Client wired to the server (to be passed to
+// youtube.WithClient).
+func (srv *Server) Client() *Client {
+	return &Client{
+		srv: srv,
+		client: &http.Client{
+			Transport: &serverTransport{srv: srv},
+		},
+
+		defaultHeader: http.Header{},
+	}
+}
+
+// NOTE: there is no underlying TLS client; always returns nil.
+func (c *Client) HTTPClient() tlsclient.HttpClient { return nil }
+
+func (c *Client) DefaultHeader() http.Header               { return c.defaultHeader }
+func (c *Client) SetDefaultHeader(header http.Header)      { c.defaultHeader = header }
+func (c *Client) Logger() *slog.Logger                     { return c.logger }
+func (c *Client) SetLogger(logger *slog.Logger)            { c.logger = logger }
+func (c *Client) CookieJar() fhttp.CookieJar               { return nil }
+func (c *Client) SetCookieJar(jar fhttp.CookieJar)         {}
+func (c *Client) SetCookies(u *url.URL, _ []*fhttp.Cookie) {}
+
+func (c *Client) Log(ctx context.Context, params clog.Params) {
+	if c.logger == nil {
+		return
+	}
+
+	clog.WithParams(c.logger, ctx, params)
+}
+
+func (c *Client) Request(ctx context.Context, method string, rawURL string, body io.Reader, headers http.Header) (*fhttp.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	} else if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range c.defaultHeader {
+		req.Header[k] = v
+	}
+	for k, v := range headers {
+		req.Header[http.CanonicalHeaderKey(k)] = v
+	}
+
+	lp := clog.Params{
+		Message: "request",
+		Level:   slog.LevelDebug,
+
+		Values: clog.ParamGroup{
+			"method": method,
+			"url":    rawURL,
+		},
+	}
+
+	resp, err := c.client.Do(req)
+	if err == nil {
+		lp.Set("status", resp.StatusCode)
+	}
+
+	lp.Err = err
+	c.Log(ctx, lp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: the final request (i.e., after redirects), as with a real client.
+	freq, err := fhttp.NewRequestWithContext(ctx, resp.Request.Method, resp.Request.URL.String(), nil)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return &fhttp.Response{
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+		Proto:      resp.Proto,
+		ProtoMajor: resp.ProtoMajor,
+		ProtoMinor: resp.ProtoMinor,
+
+		Header:        fhttp.Header(resp.Header),
+		Body:          resp.Body,
+		ContentLength: resp.ContentLength,
+
+		Request: freq,
+	}, nil
+}
+
+// http.RoundTripper which sends every request to the fake server, keeping its
+// original host in the Host header (so that redirects are followed, and
+// reported, with their original URLs).
+type serverTransport struct {
+	srv *Server
+}
+
+func (t *serverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base, err := url.Parse(t.srv.URL())
+	if err != nil {
+		return nil, err
+	}
+
+	r := req.Clone(req.Context())
+	r.URL.Scheme = base.Scheme
+	r.URL.Host = base.Host
+	r.Host = req.URL.Host
+
+	resp, err := t.srv.srv.Client().Transport.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	resp.Request = req
+
+	return resp, nil
+}
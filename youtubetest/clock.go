@@ -0,0 +1,44 @@
+package youtubetest
+
+import (
+	"sync"
+	"time"
+
+	cyoutube "github.com/rubpy/crawly-live-youtube"
+)
+
+//////////////////////////////////////////////////
+
+// Manually controlled youtube.Clock (time only moves when told to).
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+var _ cyoutube.Clock = (*Clock)(nil)
+
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *Clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+}
+
+func (c *Clock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	return c.now
+}
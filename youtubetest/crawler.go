@@ -0,0 +1,38 @@
+package youtubetest
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/youtube/v3"
+
+	cyoutube "github.com/rubpy/crawly-live-youtube"
+)
+
+//////////////////////////////////////////////////
+
+// Returns a YouTube Data API service whose endpoint is the fake server.
+func (srv *Server) Service(ctx context.Context) (*youtube.Service, error) {
+	return youtube.NewService(ctx,
+		option.WithEndpoint(srv.URL()+"/"),
+		option.WithHTTPClient(srv.srv.Client()),
+		option.WithoutAuthentication(),
+	)
+}
+
+// Builds a crawler wired to the fake server (both its client and its
+// service); opts are applied afterwards.
+func (srv *Server) NewCrawler(ctx context.Context, opts ...cyoutube.ConfigOption) (*cyoutube.Crawler, error) {
+	svc, err := srv.Service(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("youtube.NewService: %w", err)
+	}
+
+	opts = append([]cyoutube.ConfigOption{
+		cyoutube.WithClient(srv.Client()),
+		cyoutube.WithService(svc),
+	}, opts...)
+
+	return cyoutube.NewCrawler(opts...)
+}
@@ -0,0 +1,357 @@
+package youtubetest
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+//////////////////////////////////////////////////
+
+type Channel struct {
+	ID     string
+	Handle string // (e.g., "@LofiGirl"; optional)
+	Title  string
+
+	// Shown on the channel page.
+	Description string
+	Subscribers uint64
+	Verified    bool
+
+	// Set to make the channel page report the account as terminated (and the
+	// feed respond with 404).
+	Terminated bool
+}
+
+type Video struct {
+	ID          string
+	ChannelID   string
+	Title       string
+	Description string
+
+	Published time.Time
+	Updated   time.Time
+
+	// Reported in the feed's media:community element.
+	Views   uint64
+	Ratings uint64
+
+	// Whether the video is a (scheduled, ongoing or finished) livestream;
+	// only livestreams have a "_live" thumbnail.
+	Livestream bool
+
+	ScheduledStartTime time.Time
+	ActualStartTime    time.Time
+	ActualEndTime      time.Time
+	ConcurrentViewers  uint64
+
+	// Length of the video; for a premiere, it is known (and reported by the
+	// Data API) before it starts, whereas that of a livestream is zero until
+	// it ends.
+	Duration time.Duration
+}
+
+// A playlist of videos (possibly of various channels), e.g., a network's
+// "Live" playlist.
+type Playlist struct {
+	ID        string
+	ChannelID string // (owner)
+	Title     string
+
+	VideoIDs []string
+}
+
+func (v *Video) Live() bool {
+	return v.Livestream && !v.ActualStartTime.IsZero() && v.ActualEndTime.IsZero()
+}
+
+var (
+	UnknownChannel  = errors.New("unknown channel")
+	UnknownVideo    = errors.New("unknown video")
+	UnknownPlaylist = errors.New("unknown playlist")
+	DuplicateID     = errors.New("duplicate ID")
+)
+
+//////////////////////////////////////////////////
+
+func (srv *Server) now() time.Time {
+	if srv.Now != nil {
+		return srv.Now()
+	}
+
+	return time.Now()
+}
+
+// Adds a channel to the model.
+func (srv *Server) AddChannel(channel Channel) error {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if _, exists := srv.channels[channel.ID]; exists {
+		return DuplicateID
+	}
+
+	c := channel
+	srv.channels[c.ID] = &c
+
+	return nil
+}
+
+// Marks a channel as terminated (or restores it).
+func (srv *Server) SetTerminated(channelID string, terminated bool) error {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	c, ok := srv.channels[channelID]
+	if !ok {
+		return UnknownChannel
+	}
+
+	c.Terminated = terminated
+	return nil
+}
+
+// Removes a channel (along with its videos) from the model, as if it had been
+// deleted by its owner.
+func (srv *Server) DeleteChannel(channelID string) error {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if _, ok := srv.channels[channelID]; !ok {
+		return UnknownChannel
+	}
+
+	delete(srv.channels, channelID)
+	for id, v := range srv.videos {
+		if v.ChannelID == channelID {
+			delete(srv.videos, id)
+		}
+	}
+
+	return nil
+}
+
+// Adds a playlist to the model.
+func (srv *Server) AddPlaylist(playlist Playlist) error {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if _, ok := srv.channels[playlist.ChannelID]; !ok {
+		return UnknownChannel
+	}
+	if _, exists := srv.playlists[playlist.ID]; exists {
+		return DuplicateID
+	}
+
+	p := playlist
+	p.VideoIDs = append([]string(nil), playlist.VideoIDs...)
+	srv.playlists[p.ID] = &p
+
+	return nil
+}
+
+// Adds a video to the top of a playlist.
+func (srv *Server) AddToPlaylist(playlistID string, videoID string) error {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	p, ok := srv.playlists[playlistID]
+	if !ok {
+		return UnknownPlaylist
+	}
+	if _, ok := srv.videos[videoID]; !ok {
+		return UnknownVideo
+	}
+
+	p.VideoIDs = append([]string{videoID}, p.VideoIDs...)
+
+	return nil
+}
+
+func (srv *Server) addVideo(video Video) error {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if _, ok := srv.channels[video.ChannelID]; !ok {
+		return UnknownChannel
+	}
+	if _, exists := srv.videos[video.ID]; exists {
+		return DuplicateID
+	}
+
+	now := srv.now()
+	if video.Published.IsZero() {
+		video.Published = now
+	}
+	if video.Updated.IsZero() {
+		video.Updated = video.Published
+	}
+
+	v := video
+	srv.videos[v.ID] = &v
+
+	return nil
+}
+
+// Publishes a regular (i.e., non-livestream) video.
+func (srv *Server) UploadVideo(channelID string, videoID string, title string) error {
+	return srv.addVideo(Video{
+		ID:        videoID,
+		ChannelID: channelID,
+		Title:     title,
+	})
+}
+
+// Publishes an upcoming livestream scheduled to start at the given time.
+func (srv *Server) ScheduleStream(channelID string, videoID string, title string, scheduledStartTime time.Time) error {
+	return srv.addVideo(Video{
+		ID:        videoID,
+		ChannelID: channelID,
+		Title:     title,
+
+		Livestream:         true,
+		ScheduledStartTime: scheduledStartTime,
+	})
+}
+
+// Publishes an upcoming premiere (of a pre-recorded video of the given
+// duration) scheduled to start at the given time; it is started and ended
+// like a livestream.
+func (srv *Server) SchedulePremiere(channelID string, videoID string, title string, scheduledStartTime time.Time, duration time.Duration) error {
+	return srv.addVideo(Video{
+		ID:        videoID,
+		ChannelID: channelID,
+		Title:     title,
+
+		Livestream:         true,
+		ScheduledStartTime: scheduledStartTime,
+		Duration:           duration,
+	})
+}
+
+// Starts a livestream (publishing it first, if it does not exist yet).
+func (srv *Server) StartStream(channelID string, videoID string, title string) error {
+	srv.mu.Lock()
+	_, exists := srv.videos[videoID]
+	srv.mu.Unlock()
+
+	if !exists {
+		if err := srv.ScheduleStream(channelID, videoID, title, time.Time{}); err != nil {
+			return err
+		}
+	}
+
+	return srv.UpdateVideo(videoID, func(v *Video) {
+		v.Livestream = true
+		v.ActualStartTime = srv.now()
+		v.ActualEndTime = time.Time{}
+		v.Updated = v.ActualStartTime
+	})
+}
+
+// Ends an ongoing livestream.
+func (srv *Server) EndStream(videoID string) error {
+	return srv.UpdateVideo(videoID, func(v *Video) {
+		v.ActualEndTime = srv.now()
+		v.ConcurrentViewers = 0
+		v.Updated = v.ActualEndTime
+	})
+}
+
+// Modifies a video in place (e.g., to set its concurrent viewer count).
+func (srv *Server) UpdateVideo(videoID string, update func(v *Video)) error {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	v, ok := srv.videos[videoID]
+	if !ok {
+		return UnknownVideo
+	}
+
+	update(v)
+	return nil
+}
+
+func (srv *Server) Video(videoID string) (video Video, ok bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	v, ok := srv.videos[videoID]
+	if ok {
+		video = *v
+	}
+
+	return
+}
+
+//////////////////////////////////////////////////
+
+func (srv *Server) channelByHandle(handle string) (c Channel, ok bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	for _, ch := range srv.channels {
+		if ch.Handle != "" && ch.Handle == handle {
+			return *ch, true
+		}
+	}
+
+	return
+}
+
+func (srv *Server) channel(channelID string) (c Channel, ok bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	ch, ok := srv.channels[channelID]
+	if ok {
+		c = *ch
+	}
+
+	return
+}
+
+// Returns the channel's videos, most recently published first.
+func (srv *Server) channelVideos(channelID string) (videos []Video) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	for _, v := range srv.videos {
+		if v.ChannelID == channelID {
+			videos = append(videos, *v)
+		}
+	}
+
+	sort.Slice(videos, func(i, j int) bool {
+		return videos[i].Published.After(videos[j].Published)
+	})
+
+	return
+}
+
+func (srv *Server) playlist(playlistID string) (p Playlist, ok bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	pl, ok := srv.playlists[playlistID]
+	if ok {
+		p = *pl
+		p.VideoIDs = append([]string(nil), pl.VideoIDs...)
+	}
+
+	return
+}
+
+// Returns the playlist's (still existing) videos, in playlist order.
+func (srv *Server) playlistVideos(p Playlist) (videos []Video) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	for _, id := range p.VideoIDs {
+		if v, ok := srv.videos[id]; ok {
+			videos = append(videos, *v)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,801 @@
+package youtubetest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//////////////////////////////////////////////////
+
+// Fake YouTube (www.youtube.com, i.ytimg.com and the Data API's videos.list,
+// channels.list and playlistItems.list), serving a scriptable model of
+// channels, videos and playlists from an httptest.Server.
+//
+// Endpoints are told apart by path alone, so every host can be pointed at the
+// same server (which is what Client does).
+type Server struct {
+	srv *httptest.Server
+	mux *http.ServeMux
+
+	overridesMu sync.RWMutex
+	overrides   map[string]http.Handler
+	overrideMux *http.ServeMux
+
+	// Returns the current time (defaults to time.Now); used for publishing
+	// and streaming timestamps. Typically set to a Clock's Now, shared with
+	// the crawler (via youtube.WithClock).
+	Now func() time.Time
+
+	mu        sync.Mutex
+	channels  map[string]*Channel
+	videos    map[string]*Video
+	playlists map[string]*Playlist
+}
+
+// Number of entries served by feeds/videos.xml (same as YouTube).
+var FeedSize = 15
+
+func NewServer() *Server {
+	srv := &Server{
+		mux: http.NewServeMux(),
+
+		channels:  map[string]*Channel{},
+		videos:    map[string]*Video{},
+		playlists: map[string]*Playlist{},
+	}
+
+	srv.mux.HandleFunc("/feeds/videos.xml", srv.handleFeed)
+	srv.mux.HandleFunc("/channel/", srv.handleChannelPage)
+	srv.mux.HandleFunc("/watch", srv.handleWatchPage)
+	srv.mux.HandleFunc("/vi/", srv.handleThumbnail)
+	srv.mux.HandleFunc("/youtube/v3/videos", srv.handleAPIVideos)
+	srv.mux.HandleFunc("/youtube/v3/channels", srv.handleAPIChannels)
+	srv.mux.HandleFunc("/youtube/v3/playlistItems", srv.handleAPIPlaylistItems)
+	srv.mux.HandleFunc("/", srv.handleRoot)
+
+	srv.srv = httptest.NewServer(http.HandlerFunc(srv.serveHTTP))
+
+	return srv
+}
+
+func (srv *Server) Close() {
+	srv.srv.Close()
+}
+
+func (srv *Server) URL() string {
+	return srv.srv.URL
+}
+
+// Allows registering additional (or overriding) handlers, e.g., to simulate
+// errors; registering a pattern again replaces its handler, and a nil handler
+// removes it.
+func (srv *Server) Handle(pattern string, handler http.Handler) {
+	srv.overridesMu.Lock()
+	defer srv.overridesMu.Unlock()
+
+	if srv.overrides == nil {
+		srv.overrides = map[string]http.Handler{}
+	}
+	if handler == nil {
+		delete(srv.overrides, pattern)
+	} else {
+		srv.overrides[pattern] = handler
+	}
+
+	srv.overrideMux = http.NewServeMux()
+	for p, h := range srv.overrides {
+		srv.overrideMux.Handle(p, h)
+	}
+}
+
+func (srv *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	srv.overridesMu.RLock()
+	overrideMux := srv.overrideMux
+	srv.overridesMu.RUnlock()
+
+	if overrideMux != nil {
+		if h, pattern := overrideMux.Handler(r); pattern != "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+	}
+
+	srv.mux.ServeHTTP(w, r)
+}
+
+//////////////////////////////////////////////////
+
+func (srv *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
+	if handle, ok := strings.CutPrefix(r.URL.Path, "/"); ok && strings.HasPrefix(handle, "@") {
+		handle, tab, _ := strings.Cut(handle, "/")
+
+		c, ok := srv.channelByHandle(handle)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		srv.writeChannelPage(w, c, tab)
+		return
+	}
+
+	http.NotFound(w, r)
+}
+
+func (srv *Server) handleChannelPage(w http.ResponseWriter, r *http.Request) {
+	channelID := strings.TrimPrefix(r.URL.Path, "/channel/")
+	channelID, tab, _ := strings.Cut(channelID, "/")
+
+	c, ok := srv.channel(channelID)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	srv.writeChannelPage(w, c, tab)
+}
+
+// Writes the channel's home page or, if tab is "streams", its "Live" tab.
+func (srv *Server) writeChannelPage(w http.ResponseWriter, c Channel, tab string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if c.Terminated {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `<!DOCTYPE html><html><head><title>YouTube</title></head><body>`)
+		fmt.Fprint(w, `<div class="message">This account has been terminated for a violation of YouTube's Terms of Service.</div>`)
+		fmt.Fprint(w, `</body></html>`)
+		return
+	}
+
+	title := xmlEscape(c.Title)
+	fmt.Fprintf(w, `<!DOCTYPE html><html><head><title>%s - YouTube</title>`, title)
+	fmt.Fprintf(w, `<link rel="canonical" href="https://www.youtube.com/channel/%s">`, c.ID)
+	fmt.Fprintf(w, `<link rel="alternate" type="application/rss+xml" title="RSS" href="https://www.youtube.com/feeds/videos.xml?channel_id=%s">`, c.ID)
+	fmt.Fprintf(w, `<meta property="og:title" content="%s">`, title)
+	fmt.Fprintf(w, `<meta property="og:image" content="%s">`, channelAvatarURL(c))
+	fmt.Fprintf(w, `<meta property="og:description" content="%s">`, xmlEscape(c.Description))
+	fmt.Fprint(w, `</head><body>`)
+
+	data := channelInitialData(c)
+	if tab == "streams" {
+		data["contents"] = channelStreamsContents(srv.channelVideos(c.ID), srv.now())
+	}
+
+	initialData, _ := json.Marshal(data)
+	fmt.Fprintf(w, `<script nonce="test">var ytInitialData = %s;</script>`, initialData)
+	fmt.Fprint(w, `</body></html>`)
+}
+
+func channelAvatarURL(c Channel) string {
+	return "https://yt3.googleusercontent.com/avatar-" + c.ID + "=s900-c-k-c0x00ffffff-no-rj"
+}
+
+// Returns a (minimal) ytInitialData object of a channel page, in the layout
+// with a c4TabbedHeaderRenderer.
+func channelInitialData(c Channel) map[string]any {
+	thumbnails := func(url string) map[string]any {
+		return map[string]any{
+			"thumbnails": []any{map[string]any{"url": url, "width": 900, "height": 900}},
+		}
+	}
+
+	metadata := map[string]any{
+		"title":       c.Title,
+		"description": c.Description,
+		"externalId":  c.ID,
+		"avatar":      thumbnails(channelAvatarURL(c)),
+	}
+	if c.Handle != "" {
+		metadata["vanityChannelUrl"] = "http://www.youtube.com/" + c.Handle
+	}
+
+	header := map[string]any{
+		"channelId": c.ID,
+		"title":     c.Title,
+		"avatar":    thumbnails(channelAvatarURL(c)),
+		"banner":    thumbnails("https://yt3.googleusercontent.com/banner-" + c.ID + "=w1060"),
+		"subscriberCountText": map[string]any{
+			"simpleText": formatSubscribers(c.Subscribers),
+		},
+	}
+	if c.Handle != "" {
+		header["channelHandleText"] = map[string]any{
+			"runs": []any{map[string]any{"text": c.Handle}},
+		}
+	}
+	if c.Verified {
+		header["badges"] = []any{map[string]any{
+			"metadataBadgeRenderer": map[string]any{"style": "BADGE_STYLE_TYPE_VERIFIED", "tooltip": "Verified"},
+		}}
+	}
+
+	return map[string]any{
+		"header":   map[string]any{"c4TabbedHeaderRenderer": header},
+		"metadata": map[string]any{"channelMetadataRenderer": metadata},
+	}
+}
+
+// Returns the "contents" of a channel's "Live" tab ytInitialData, listing its
+// livestreams (upcoming, live and finished) as videoRenderers.
+func channelStreamsContents(videos []Video, now time.Time) map[string]any {
+	items := make([]any, 0, len(videos))
+	for _, v := range videos {
+		if !v.Livestream {
+			continue
+		}
+
+		renderer := map[string]any{
+			"videoId": v.ID,
+			"title": map[string]any{
+				"runs": []any{map[string]any{"text": v.Title}},
+			},
+		}
+
+		switch {
+		case v.Live():
+			renderer["viewCountText"] = map[string]any{
+				"runs": []any{map[string]any{"text": fmt.Sprint(v.ConcurrentViewers)}, map[string]any{"text": " watching"}},
+			}
+			renderer["badges"] = []any{map[string]any{
+				"metadataBadgeRenderer": map[string]any{"style": "BADGE_STYLE_TYPE_LIVE_NOW", "label": "LIVE"},
+			}}
+			renderer["thumbnailOverlays"] = []any{map[string]any{
+				"thumbnailOverlayTimeStatusRenderer": map[string]any{"style": "LIVE"},
+			}}
+
+		case v.ActualStartTime.IsZero():
+			if !v.ScheduledStartTime.IsZero() {
+				renderer["upcomingEventData"] = map[string]any{
+					"startTime": fmt.Sprint(v.ScheduledStartTime.Unix()),
+				}
+			}
+			renderer["thumbnailOverlays"] = []any{map[string]any{
+				"thumbnailOverlayTimeStatusRenderer": map[string]any{"style": "UPCOMING"},
+			}}
+
+		default:
+			renderer["viewCountText"] = map[string]any{"simpleText": fmt.Sprintf("%d views", v.Views)}
+			renderer["publishedTimeText"] = map[string]any{
+				"simpleText": "Streamed " + formatAgo(now.Sub(v.ActualStartTime)),
+			}
+		}
+
+		items = append(items, map[string]any{
+			"richItemRenderer": map[string]any{
+				"content": map[string]any{"videoRenderer": renderer},
+			},
+		})
+	}
+
+	return map[string]any{
+		"twoColumnBrowseResultsRenderer": map[string]any{
+			"tabs": []any{map[string]any{
+				"tabRenderer": map[string]any{
+					"title":    "Live",
+					"selected": true,
+					"content": map[string]any{
+						"richGridRenderer": map[string]any{"contents": items},
+					},
+				},
+			}},
+		},
+	}
+}
+
+// Formats a duration the way YouTube does relative times (e.g., "3 hours
+// ago").
+func formatAgo(d time.Duration) string {
+	unit, n := "minute", int64(d/time.Minute)
+	switch {
+	case d >= 24*time.Hour:
+		unit, n = "day", int64(d/(24*time.Hour))
+	case d >= time.Hour:
+		unit, n = "hour", int64(d/time.Hour)
+	}
+
+	if n == 1 {
+		return "1 " + unit + " ago"
+	}
+
+	return fmt.Sprintf("%d %ss ago", n, unit)
+}
+
+// Formats a subscriber count the way YouTube abbreviates it (e.g., "1.23M
+// subscribers").
+func formatSubscribers(n uint64) string {
+	switch {
+	case n == 0:
+		return "No subscribers"
+	case n == 1:
+		return "1 subscriber"
+	case n < 1e3:
+		return fmt.Sprintf("%d subscribers", n)
+	case n < 1e6:
+		return fmt.Sprintf("%.3gK subscribers", float64(n)/1e3)
+	}
+
+	return fmt.Sprintf("%.3gM subscribers", float64(n)/1e6)
+}
+
+func (srv *Server) handleWatchPage(w http.ResponseWriter, r *http.Request) {
+	v, ok := srv.Video(r.URL.Query().Get("v"))
+	if !ok {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<!DOCTYPE html><html><head><title>YouTube</title></head><body>`)
+		fmt.Fprint(w, `<script nonce="test">var ytInitialPlayerResponse = {"playabilityStatus":{"status":"ERROR","reason":"Video unavailable"}};</script>`)
+		fmt.Fprint(w, `</body></html>`)
+		return
+	}
+
+	playerResponse, _ := json.Marshal(videoPlayerResponse(v))
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, `<!DOCTYPE html><html><head><title>%s - YouTube</title>`, xmlEscape(v.Title))
+	fmt.Fprintf(w, `<link rel="canonical" href="https://www.youtube.com/watch?v=%s">`, v.ID)
+	fmt.Fprint(w, `</head><body>`)
+	fmt.Fprintf(w, `<script nonce="test">var ytInitialPlayerResponse = %s;var meta = document.createElement('meta');</script>`, playerResponse)
+	fmt.Fprint(w, `</body></html>`)
+}
+
+// Returns a (minimal) ytInitialPlayerResponse object of a watch page.
+func videoPlayerResponse(v Video) map[string]any {
+	upcoming := v.Livestream && v.ActualStartTime.IsZero()
+
+	status := map[string]any{"status": "OK"}
+	if upcoming {
+		status["status"] = "LIVE_STREAM_OFFLINE"
+		if !v.ScheduledStartTime.IsZero() {
+			status["liveStreamability"] = map[string]any{
+				"liveStreamabilityRenderer": map[string]any{
+					"videoId": v.ID,
+					"offlineSlate": map[string]any{
+						"liveStreamOfflineSlateRenderer": map[string]any{
+							"scheduledStartTime": fmt.Sprint(v.ScheduledStartTime.Unix()),
+						},
+					},
+				},
+			}
+		}
+	}
+
+	details := map[string]any{
+		"videoId":       v.ID,
+		"channelId":     v.ChannelID,
+		"title":         v.Title,
+		"viewCount":     fmt.Sprint(v.Views),
+		"isLiveContent": v.Livestream,
+	}
+	if v.Live() {
+		details["isLive"] = true
+	}
+	if upcoming {
+		details["isUpcoming"] = true
+	}
+
+	microformat := map[string]any{
+		"externalChannelId": v.ChannelID,
+		"publishDate":       formatTime(v.Published),
+	}
+	if v.Livestream {
+		lbd := map[string]any{
+			"isLiveNow": v.Live(),
+		}
+		if !v.ActualStartTime.IsZero() {
+			lbd["startTimestamp"] = formatTime(v.ActualStartTime)
+		}
+		if !v.ActualEndTime.IsZero() {
+			lbd["endTimestamp"] = formatTime(v.ActualEndTime)
+		}
+
+		microformat["liveBroadcastDetails"] = lbd
+	}
+
+	return map[string]any{
+		"playabilityStatus": status,
+		"videoDetails":      details,
+		"microformat":       map[string]any{"playerMicroformatRenderer": microformat},
+	}
+}
+
+func (srv *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	if playlistID := r.URL.Query().Get("playlist_id"); playlistID != "" {
+		srv.handlePlaylistFeed(w, r, playlistID)
+		return
+	}
+
+	channelID := r.URL.Query().Get("channel_id")
+
+	c, ok := srv.channel(channelID)
+	if !ok || c.Terminated {
+		http.NotFound(w, r)
+		return
+	}
+
+	videos := srv.channelVideos(channelID)
+	if len(videos) > FeedSize {
+		videos = videos[:FeedSize]
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+	writeFeed(w, feedHeader{
+		idTag:   fmt.Sprintf(`<id>yt:channel:%s</id>`, strings.TrimPrefix(c.ID, "UC")),
+		selfURL: "http://www.youtube.com/feeds/videos.xml?channel_id=" + c.ID,
+		url:     "https://www.youtube.com/channel/" + c.ID,
+		title:   c.Title,
+		channel: c,
+	}, videos, func(Video) Channel { return c })
+}
+
+func (srv *Server) handlePlaylistFeed(w http.ResponseWriter, r *http.Request, playlistID string) {
+	p, ok := srv.playlist(playlistID)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	owner, _ := srv.channel(p.ChannelID)
+
+	videos := srv.playlistVideos(p)
+	if len(videos) > FeedSize {
+		videos = videos[:FeedSize]
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+	writeFeed(w, feedHeader{
+		idTag:   fmt.Sprintf(`<id>yt:playlist:%s</id><yt:playlistId>%s</yt:playlistId>`, p.ID, p.ID),
+		selfURL: "http://www.youtube.com/feeds/videos.xml?playlist_id=" + p.ID,
+		url:     "https://www.youtube.com/playlist?list=" + p.ID,
+		title:   p.Title,
+		channel: owner,
+	}, videos, func(v Video) Channel {
+		c, _ := srv.channel(v.ChannelID)
+		return c
+	})
+}
+
+func (srv *Server) handleThumbnail(w http.ResponseWriter, r *http.Request) {
+	// e.g., /vi/<videoID>/sddefault_live.jpg
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/vi/"), "/")
+	if len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+
+	v, ok := srv.Video(parts[0])
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if strings.Contains(parts[1], "_live.") && !v.Livestream {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Write([]byte{0xff, 0xd8, 0xff, 0xd9})
+}
+
+func (srv *Server) handleAPIVideos(w http.ResponseWriter, r *http.Request) {
+	type liveStreamingDetails struct {
+		ScheduledStartTime string `json:"scheduledStartTime,omitempty"`
+		ActualStartTime    string `json:"actualStartTime,omitempty"`
+		ActualEndTime      string `json:"actualEndTime,omitempty"`
+		ConcurrentViewers  uint64 `json:"concurrentViewers,omitempty,string"`
+	}
+	type contentDetails struct {
+		Duration string `json:"duration"`
+	}
+	type snippet struct {
+		PublishedAt  string `json:"publishedAt"`
+		ChannelID    string `json:"channelId"`
+		Title        string `json:"title"`
+		Description  string `json:"description"`
+		ChannelTitle string `json:"channelTitle"`
+	}
+	type item struct {
+		Kind                 string                `json:"kind"`
+		ID                   string                `json:"id"`
+		Snippet              *snippet              `json:"snippet,omitempty"`
+		ContentDetails       *contentDetails       `json:"contentDetails,omitempty"`
+		LiveStreamingDetails *liveStreamingDetails `json:"liveStreamingDetails,omitempty"`
+	}
+
+	resp := struct {
+		Kind  string `json:"kind"`
+		Items []item `json:"items"`
+	}{
+		Kind:  "youtube#videoListResponse",
+		Items: []item{},
+	}
+
+	for _, ids := range r.URL.Query()["id"] {
+		for _, id := range strings.Split(ids, ",") {
+			v, ok := srv.Video(id)
+			if !ok {
+				continue
+			}
+
+			it := item{
+				Kind: "youtube#video",
+				ID:   v.ID,
+			}
+			if hasPart(r, "snippet") {
+				c, _ := srv.channel(v.ChannelID)
+
+				it.Snippet = &snippet{
+					PublishedAt:  formatTime(v.Published),
+					ChannelID:    v.ChannelID,
+					Title:        v.Title,
+					Description:  v.Description,
+					ChannelTitle: c.Title,
+				}
+			}
+			if hasPart(r, "contentDetails") {
+				// NOTE: a livestream's duration is only known once it has ended.
+				duration := v.Duration
+				if v.Livestream && duration == 0 && !v.ActualEndTime.IsZero() {
+					duration = v.ActualEndTime.Sub(v.ActualStartTime)
+				}
+
+				it.ContentDetails = &contentDetails{Duration: formatDuration(duration)}
+			}
+			if v.Livestream {
+				it.LiveStreamingDetails = &liveStreamingDetails{
+					ScheduledStartTime: formatTime(v.ScheduledStartTime),
+					ActualStartTime:    formatTime(v.ActualStartTime),
+					ActualEndTime:      formatTime(v.ActualEndTime),
+					ConcurrentViewers:  v.ConcurrentViewers,
+				}
+			}
+
+			resp.Items = append(resp.Items, it)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (srv *Server) handleAPIChannels(w http.ResponseWriter, r *http.Request) {
+	type relatedPlaylists struct {
+		Uploads string `json:"uploads"`
+	}
+	type contentDetails struct {
+		RelatedPlaylists relatedPlaylists `json:"relatedPlaylists"`
+	}
+	type item struct {
+		Kind           string          `json:"kind"`
+		ID             string          `json:"id"`
+		ContentDetails *contentDetails `json:"contentDetails,omitempty"`
+	}
+
+	withContentDetails := hasPart(r, "contentDetails")
+
+	resp := struct {
+		Kind  string `json:"kind"`
+		Items []item `json:"items"`
+	}{
+		Kind:  "youtube#channelListResponse",
+		Items: []item{},
+	}
+
+	for _, ids := range r.URL.Query()["id"] {
+		for _, id := range strings.Split(ids, ",") {
+			c, ok := srv.channel(id)
+			if !ok || c.Terminated {
+				continue
+			}
+
+			it := item{
+				Kind: "youtube#channel",
+				ID:   c.ID,
+			}
+			if withContentDetails {
+				it.ContentDetails = &contentDetails{
+					RelatedPlaylists: relatedPlaylists{Uploads: UploadsPlaylistID(c.ID)},
+				}
+			}
+
+			resp.Items = append(resp.Items, it)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Serves both the uploads playlists of channels (see UploadsPlaylistID) and
+// playlists added via AddPlaylist, paged via pageToken (an offset).
+func (srv *Server) handleAPIPlaylistItems(w http.ResponseWriter, r *http.Request) {
+	type contentDetails struct {
+		VideoID          string `json:"videoId"`
+		VideoPublishedAt string `json:"videoPublishedAt,omitempty"`
+	}
+	type item struct {
+		Kind           string         `json:"kind"`
+		ID             string         `json:"id"`
+		ContentDetails contentDetails `json:"contentDetails"`
+	}
+
+	q := r.URL.Query()
+	playlistID := q.Get("playlistId")
+
+	var videos []Video
+	if channelID, ok := strings.CutPrefix(playlistID, "UU"); ok {
+		if c, ok := srv.channel("UC" + channelID); ok && !c.Terminated {
+			videos = srv.channelVideos(c.ID)
+		} else {
+			playlistID = ""
+		}
+	} else if p, ok := srv.playlist(playlistID); ok {
+		videos = srv.playlistVideos(p)
+	} else {
+		playlistID = ""
+	}
+
+	if playlistID == "" {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":404,"message":"The playlist identified with the request's playlistId parameter cannot be found.","errors":[{"reason":"playlistNotFound"}]}}`)
+		return
+	}
+
+	offset, _ := strconv.Atoi(q.Get("pageToken"))
+	maxResults, err := strconv.Atoi(q.Get("maxResults"))
+	if err != nil || maxResults <= 0 {
+		maxResults = 5
+	}
+
+	resp := struct {
+		Kind          string `json:"kind"`
+		NextPageToken string `json:"nextPageToken,omitempty"`
+		Items         []item `json:"items"`
+	}{
+		Kind:  "youtube#playlistItemListResponse",
+		Items: []item{},
+	}
+
+	for i := offset; i >= 0 && i < len(videos) && len(resp.Items) < maxResults; i++ {
+		v := videos[i]
+		resp.Items = append(resp.Items, item{
+			Kind: "youtube#playlistItem",
+			ID:   playlistID + "." + v.ID,
+			ContentDetails: contentDetails{
+				VideoID:          v.ID,
+				VideoPublishedAt: formatTime(v.Published),
+			},
+		})
+	}
+	if next := offset + len(resp.Items); next < len(videos) {
+		resp.NextPageToken = strconv.Itoa(next)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Returns the ID of a channel's uploads playlist (i.e., "UC..." becomes
+// "UU...").
+func UploadsPlaylistID(channelID string) string {
+	return "UU" + strings.TrimPrefix(channelID, "UC")
+}
+
+//////////////////////////////////////////////////
+
+// Reports whether a Data API request asks for the given part (the client
+// library repeats the "part" parameter, rather than joining the parts).
+func hasPart(r *http.Request, name string) bool {
+	for _, parts := range r.URL.Query()["part"] {
+		for _, part := range strings.Split(parts, ",") {
+			if part == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Formats a duration the way the Data API does (ISO 8601, e.g. "PT1H2M3S";
+// zero is "P0D").
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "P0D"
+	}
+
+	var s strings.Builder
+	s.WriteString("P")
+	if days := d / (24 * time.Hour); days > 0 {
+		fmt.Fprintf(&s, "%dD", days)
+		d -= days * 24 * time.Hour
+	}
+	if d > 0 {
+		s.WriteString("T")
+		if h := d / time.Hour; h > 0 {
+			fmt.Fprintf(&s, "%dH", h)
+			d -= h * time.Hour
+		}
+		if m := d / time.Minute; m > 0 {
+			fmt.Fprintf(&s, "%dM", m)
+			d -= m * time.Minute
+		}
+		if sec := d / time.Second; sec > 0 {
+			fmt.Fprintf(&s, "%dS", sec)
+		}
+	}
+
+	return s.String()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func starRatingAverage(ratings uint64) string {
+	if ratings == 0 {
+		return "0.00"
+	}
+
+	return "5.00"
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+
+	return b.String()
+}
+
+type feedHeader struct {
+	idTag   string
+	selfURL string
+	url     string
+	title   string
+	channel Channel // (owner)
+}
+
+func writeFeed(w http.ResponseWriter, h feedHeader, videos []Video, author func(v Video) Channel) {
+	fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprint(w, `<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">`+"\n")
+	fmt.Fprintf(w, ` <link rel="self" href="%s"/>`+"\n", xmlEscape(h.selfURL))
+	fmt.Fprintf(w, ` %s`+"\n", h.idTag)
+	fmt.Fprintf(w, ` <yt:channelId>%s</yt:channelId>`+"\n", h.channel.ID)
+	fmt.Fprintf(w, ` <title>%s</title>`+"\n", xmlEscape(h.title))
+	fmt.Fprintf(w, ` <link rel="alternate" href="%s"/>`+"\n", xmlEscape(h.url))
+	fmt.Fprintf(w, ` <author><name>%s</name><uri>https://www.youtube.com/channel/%s</uri></author>`+"\n", xmlEscape(h.channel.Title), h.channel.ID)
+
+	for _, v := range videos {
+		c := author(v)
+
+		fmt.Fprint(w, ` <entry>`+"\n")
+		fmt.Fprintf(w, `  <id>yt:video:%s</id>`+"\n", v.ID)
+		fmt.Fprintf(w, `  <yt:videoId>%s</yt:videoId>`+"\n", v.ID)
+		fmt.Fprintf(w, `  <yt:channelId>%s</yt:channelId>`+"\n", v.ChannelID)
+		fmt.Fprintf(w, `  <title>%s</title>`+"\n", xmlEscape(v.Title))
+		fmt.Fprintf(w, `  <link rel="alternate" href="https://www.youtube.com/watch?v=%s"/>`+"\n", v.ID)
+		fmt.Fprintf(w, `  <author><name>%s</name><uri>https://www.youtube.com/channel/%s</uri></author>`+"\n", xmlEscape(c.Title), v.ChannelID)
+		fmt.Fprintf(w, `  <published>%s</published>`+"\n", formatTime(v.Published))
+		fmt.Fprintf(w, `  <updated>%s</updated>`+"\n", formatTime(v.Updated))
+		fmt.Fprint(w, `  <media:group>`+"\n")
+		fmt.Fprintf(w, `   <media:title>%s</media:title>`+"\n", xmlEscape(v.Title))
+		fmt.Fprintf(w, `   <media:content url="https://www.youtube.com/v/%s?version=3" type="application/x-shockwave-flash" width="640" height="390"/>`+"\n", v.ID)
+		fmt.Fprintf(w, `   <media:thumbnail url="https://i4.ytimg.com/vi/%s/hqdefault.jpg" width="480" height="360"/>`+"\n", v.ID)
+		fmt.Fprintf(w, `   <media:description>%s</media:description>`+"\n", xmlEscape(v.Description))
+		fmt.Fprintf(w, `   <media:community><media:starRating count="%d" average="%s" min="1" max="5"/><media:statistics views="%d"/></media:community>`+"\n", v.Ratings, starRatingAverage(v.Ratings), v.Views)
+		fmt.Fprint(w, `  </media:group>`+"\n")
+		fmt.Fprint(w, ` </entry>`+"\n")
+	}
+
+	fmt.Fprint(w, `</feed>`+"\n")
+}
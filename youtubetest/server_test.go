@@ -0,0 +1,164 @@
+package youtubetest_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rubpy/crawly-live-youtube/xmlapi"
+	"github.com/rubpy/crawly-live-youtube/youtubetest"
+)
+
+const testChannelID = "UCSJ4gkVC6NrvII8umztf0Ow"
+
+func newTestServer(t *testing.T) *youtubetest.Server {
+	t.Helper()
+
+	srv := youtubetest.NewServer()
+	t.Cleanup(srv.Close)
+
+	clk := youtubetest.NewClock(time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC))
+	srv.Now = clk.Now
+
+	if err := srv.AddChannel(youtubetest.Channel{ID: testChannelID, Handle: "@Test", Title: "Test", Subscribers: 1230000}); err != nil {
+		t.Fatalf("AddChannel: %v", err)
+	}
+
+	return srv
+}
+
+func TestServerRoutes(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+
+	if err := srv.StartStream(testChannelID, "aaaaaaaaaaa", "Stream"); err != nil {
+		t.Fatalf("StartStream: %v", err)
+	}
+	if err := srv.UploadVideo(testChannelID, "bbbbbbbbbbb", "Upload"); err != nil {
+		t.Fatalf("UploadVideo: %v", err)
+	}
+
+	tests := []struct {
+		url    string
+		status int
+	}{
+		{"https://www.youtube.com/feeds/videos.xml?channel_id=" + testChannelID, http.StatusOK},
+		{"https://www.youtube.com/feeds/videos.xml?channel_id=UCxxxxxxxxxxxxxxxxxxxxxx", http.StatusNotFound},
+		{"https://www.youtube.com/channel/" + testChannelID, http.StatusOK},
+		{"https://www.youtube.com/channel/" + testChannelID + "/streams", http.StatusOK},
+		{"https://www.youtube.com/@Test", http.StatusOK},
+		{"https://www.youtube.com/@Unknown", http.StatusNotFound},
+		{"https://www.youtube.com/watch?v=aaaaaaaaaaa", http.StatusOK},
+		{"https://i.ytimg.com/vi/aaaaaaaaaaa/maxresdefault_live.jpg", http.StatusOK},
+		{"https://i.ytimg.com/vi/bbbbbbbbbbb/maxresdefault_live.jpg", http.StatusNotFound},
+		{"https://www.googleapis.com/youtube/v3/playlistItems?part=contentDetails&playlistId=" + youtubetest.UploadsPlaylistID(testChannelID), http.StatusOK},
+		{"https://www.googleapis.com/youtube/v3/playlistItems?part=contentDetails&playlistId=PLunknown", http.StatusNotFound},
+		{"https://www.youtube.com/unknown", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		resp, err := client.Request(context.Background(), http.MethodGet, tt.url, nil, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.url, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: got status %d, want %d", tt.url, resp.StatusCode, tt.status)
+		}
+	}
+}
+
+func TestServerFeed(t *testing.T) {
+	srv := newTestServer(t)
+
+	if err := srv.UploadVideo(testChannelID, "bbbbbbbbbbb", "Upload"); err != nil {
+		t.Fatalf("UploadVideo: %v", err)
+	}
+
+	resp, err := srv.Client().Request(context.Background(), http.MethodGet, "https://www.youtube.com/feeds/videos.xml?channel_id="+testChannelID, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	feed, err := xmlapi.ParseChannelFeedReader(resp.Body, 0)
+	if err != nil {
+		t.Fatalf("ParseChannelFeedReader: %v", err)
+	}
+
+	videos := feed.Videos()
+	if len(videos) != 1 || videos[0].ID != "bbbbbbbbbbb" || videos[0].ChannelID != testChannelID || videos[0].Title != "Upload" {
+		t.Fatalf("got feed videos %+v", videos)
+	}
+}
+
+func TestServerChannelPage(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp, err := srv.Client().Request(context.Background(), http.MethodGet, "https://www.youtube.com/@Test", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	index, err := xmlapi.ParseChannelPageReader(resp.Body, 0)
+	if err != nil {
+		t.Fatalf("ParseChannelPageReader: %v", err)
+	}
+
+	if index.ChannelID != testChannelID || index.Handle != "@Test" || index.Title != "Test" || index.Subscribers != 1230000 {
+		t.Fatalf("got channel index %+v", index)
+	}
+
+	if err := srv.SetTerminated(testChannelID, true); err != nil {
+		t.Fatalf("SetTerminated: %v", err)
+	}
+
+	resp, err = srv.Client().Request(context.Background(), http.MethodGet, "https://www.youtube.com/channel/"+testChannelID, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("got status %d for a terminated channel, want 404", resp.StatusCode)
+	}
+}
+
+func TestServerRedirect(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+
+	srv.Handle("/@Old", http.RedirectHandler("https://www.youtube.com/@Test", http.StatusMovedPermanently))
+
+	resp, err := client.Request(context.Background(), http.MethodGet, "https://www.youtube.com/@Old", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || len(body) == 0 {
+		t.Fatalf("got status %d (%d bytes) after the redirect", resp.StatusCode, len(body))
+	}
+	// NOTE: reported with the original host, as with a real client.
+	if got := resp.Request.URL.String(); got != "https://www.youtube.com/@Test" {
+		t.Fatalf("got final URL %q, want https://www.youtube.com/@Test", got)
+	}
+
+	// Removing the handler restores the default route.
+	srv.Handle("/@Old", nil)
+
+	resp, err = client.Request(context.Background(), http.MethodGet, "https://www.youtube.com/@Old", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("got status %d after removing the handler, want 404", resp.StatusCode)
+	}
+}